@@ -0,0 +1,241 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/ci"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/logs"
+	"github.com/bjartek/aether/pkg/scenario"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog"
+)
+
+// ciSink collects init transaction results and passes log lines through
+type ciSink struct {
+	mu     sync.Mutex
+	logOut io.Writer
+	last   time.Time
+	cases  []ci.TestCase
+}
+
+// Send implements events.Sink
+func (s *ciSink) Send(msg tea.Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msg := msg.(type) {
+	case aether.InitTransactionMsg:
+		// Init transactions run one after another, so the time since the previous one is its duration
+		now := time.Now()
+		s.cases = append(s.cases, ci.TestCase{
+			Name:          msg.Filename,
+			Success:       msg.Success,
+			Error:         msg.Error,
+			TransactionID: msg.TransactionID,
+			Duration:      now.Sub(s.last),
+		})
+		s.last = now
+	case logs.LogLineMsg:
+		if s.logOut != nil {
+			_, _ = io.WriteString(s.logOut, msg.Line)
+		}
+	}
+}
+
+func (s *ciSink) initTransactions() []ci.TestCase {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ci.TestCase(nil), s.cases...)
+}
+
+// ciCommand implements `aether ci`: boot the emulator, deploy, run init transactions
+// and optionally scenarios, write reports and exit with a status code
+func ciCommand(args []string) int {
+	fs := flag.NewFlagSet("ci", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: aether ci [flags] [scenario files or folders]\n\n")
+		fmt.Fprintf(fs.Output(), "Boots the emulator, deploys contracts, runs init transactions and optionally scenarios,\n")
+		fmt.Fprintf(fs.Output(), "writes a JUnit XML and JSON report and exits non-zero if anything failed.\n\n")
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "Path to configuration file")
+	junitPath := fs.String("junit", "aether-junit.xml", "Path of the JUnit XML report (empty to skip)")
+	jsonPath := fs.String("json", "aether-report.json", "Path of the JSON report (empty to skip)")
+	runScenarios := fs.Bool("scenarios", false, "Run all scenarios in the scenarios folder after the init transactions")
+	timeout := fs.Duration("timeout", 10*time.Minute, "Maximum time for the whole run, setup and scenarios")
+	quiet := fs.Bool("quiet", false, "Do not print logs to stderr")
+	coverage := fs.String("coverage", "", "Folder to write an LCOV and JSON coverage report to (empty to skip)")
+
+	scenarioPaths, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	// CI always runs against a fresh local emulator without prompts
+	cfg.Network = "emulator"
+	cfg.Flow.InitTransactionsInteractive = false
+	cfg.Flow.Persist = false
+	// Nobody commits blocks in CI, so scenarios would wait forever
+	cfg.Flow.ManualBlocks = false
+	if *coverage != "" {
+		cfg.Flow.Coverage.Enabled = true
+		cfg.Flow.Coverage.Folder = *coverage
+	}
+
+	report := &ci.Report{StartedAt: time.Now()}
+	sink := &ciSink{last: report.StartedAt}
+	if !*quiet {
+		sink.logOut = os.Stderr
+	}
+
+	err = runCI(cfg, sink, *runScenarios, scenarioPaths, *timeout, report)
+
+	if cases := sink.initTransactions(); len(cases) > 0 {
+		report.Suites = append([]ci.Suite{{Name: "init transactions", Cases: cases}}, report.Suites...)
+	}
+	report.Finish(err)
+
+	if *junitPath != "" {
+		if err := report.WriteJUnit(*junitPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write JUnit report: %v\n", err)
+			return 1
+		}
+	}
+	if *jsonPath != "" {
+		if err := report.WriteJSON(*jsonPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write JSON report: %v\n", err)
+			return 1
+		}
+	}
+
+	for _, suite := range report.Suites {
+		fmt.Fprintf(os.Stderr, "%s: %d/%d passed\n", suite.Name, len(suite.Cases)-suite.Failures(), len(suite.Cases))
+	}
+	if !report.Success {
+		if report.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", report.Error)
+		}
+		return 1
+	}
+	return 0
+}
+
+// runCI starts the services, runs setup and scenarios and always shuts everything down again
+func runCI(cfg *config.Config, sink *ciSink, runScenarios bool, scenarioPaths []string, timeout time.Duration, report *ci.Report) error {
+	deadline := time.After(timeout)
+	if _, err := checkRequiredPorts(cfg); err != nil {
+		return err
+	}
+
+	logger, logWriter := logs.NewLogger(cfg.Logging.File.BufferSize)
+	logger = logger.Level(logs.ParseLogLevel(cfg.Logging.Level.Global))
+	log := newLoggers(logger, cfg)
+	logWriter.AttachSink(sink)
+	defer logWriter.Close()
+
+	svc, err := startServices(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer svc.stop()
+
+	a := aether.Aether{
+		Logger:   &log.aether,
+		FclCdc:   fclCdc,
+		Network:  cfg.Network,
+		Config:   cfg,
+		Emulator: svc.emulator(),
+	}
+	defer a.Stop()
+	// Runs before Stop, so scenarios are included in the report
+	defer writeCoverageOnExit(&a)
+
+	// Start blocks until contracts are deployed and init transactions have run
+	done := make(chan error, 1)
+	go func() {
+		<-svc.ready
+		done <- a.Start(sink)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("setup failed: %w", err)
+		}
+	case <-deadline:
+		return fmt.Errorf("setup did not finish within %s", timeout)
+	}
+
+	if !runScenarios && len(scenarioPaths) == 0 {
+		return nil
+	}
+
+	_, basePath, err := aether.DetectBasePath()
+	if err != nil {
+		return err
+	}
+	if len(scenarioPaths) == 0 {
+		scenarioPaths = []string{filepath.Join(basePath, scenario.DefaultFolder)}
+	}
+	scenarios, err := loadScenarios(scenarioPaths)
+	if err != nil {
+		return err
+	}
+
+	o := aether.NewRunnerOverflow(cfg, basePath)
+	if o.Error != nil {
+		return fmt.Errorf("failed to initialize overflow: %w", o.Error)
+	}
+
+	// Buffered, so a scenario still running at the deadline does not block once nobody receives
+	results := make(chan scenario.Result, len(scenarios))
+	go func() {
+		defer close(results)
+		for _, s := range scenarios {
+			results <- scenario.Run(o, s)
+		}
+	}()
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return nil
+			}
+			report.Suites = append(report.Suites, scenarioSuite(result))
+		case <-deadline:
+			// The scenarios that finished are still in the report
+			return fmt.Errorf("scenarios did not finish within %s", timeout)
+		}
+	}
+}
+
+// scenarioSuite converts a scenario result into a report suite with one case per step
+func scenarioSuite(result scenario.Result) ci.Suite {
+	suite := ci.Suite{Name: "scenario " + result.Name}
+	for _, step := range result.Steps {
+		tc := ci.TestCase{
+			Name:          fmt.Sprintf("%d. %s", step.Index, step.Name),
+			Success:       step.Passed,
+			TransactionID: step.TransactionID,
+			Duration:      step.Duration,
+		}
+		if !step.Passed {
+			tc.Error = joins(step.Failures, "; ")
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return suite
+}
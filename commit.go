@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/rs/zerolog"
+)
+
+// commitCommand implements `aether commit`: commit blocks on the emulator of a running aether,
+// used with flow.manual_blocks: true to decide which transactions end up in the same block
+func commitCommand(args []string) int {
+	fs := flag.NewFlagSet("commit", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: aether commit [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Commits the pending transactions of a running aether in one block, and optionally more empty blocks.\n\n")
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "Path to configuration file")
+	count := fs.Int("n", 1, "Number of blocks to commit")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	useRunningPorts(cfg)
+
+	block, err := aether.NewControlClient(cfg).CommitBlocks(*count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Committed %d block(s), latest height %d\n", *count, block.Height)
+	return 0
+}
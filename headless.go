@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/logs"
+)
+
+// runHeadless runs the same services as the TUI but streams every transaction and event
+// as newline-delimited JSON to stdout. Logs are written to stderr.
+// It blocks until SIGINT/SIGTERM is received.
+func runHeadless(cfg *config.Config, log loggers, logWriter *logs.LogWriter) error {
+	// There is nobody to answer the folder prompt in headless mode
+	cfg.Flow.InitTransactionsInteractive = false
+
+	sink := aether.NewNDJSONSink(os.Stdout, os.Stderr)
+	sink.OnError(func(err error) {
+		log.aether.Warn().Err(err).Msg("Failed to write NDJSON record")
+	})
+	logWriter.AttachSink(sink)
+
+	svc, err := startServices(cfg, log)
+	if err != nil {
+		return err
+	}
+
+	a := aether.Aether{
+		Logger:   &log.aether,
+		FclCdc:   fclCdc,
+		Network:  cfg.Network,
+		Config:   cfg,
+		Emulator: svc.emulator(),
+	}
+
+	svc.startFrontend(sink)
+
+	go func() {
+		<-svc.ready
+		log.aether.Info().Msg("Starting aether server")
+		if err := a.Start(sink); err != nil {
+			log.aether.Error().Err(err).Msg("Failed to start aether server")
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+
+	log.aether.Info().Msg("Shutting down...")
+	writeCoverageOnExit(&a)
+	svc.stop()
+	log.aether.Info().Msg("Stopping aether server...")
+	a.Stop()
+	log.aether.Info().Msg("All services stopped cleanly")
+	logWriter.Close()
+	return nil
+}
@@ -6,19 +6,14 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"time"
 
 	"github.com/bjartek/aether/pkg/aether"
 	"github.com/bjartek/aether/pkg/config"
-	"github.com/bjartek/aether/pkg/flow"
-	"github.com/bjartek/aether/pkg/frontend"
+	"github.com/bjartek/aether/pkg/events"
 	"github.com/bjartek/aether/pkg/logs"
 	"github.com/bjartek/aether/pkg/tabbedtui"
 	"github.com/bjartek/aether/pkg/ui"
 	tea "github.com/charmbracelet/bubbletea"
-	devWallet "github.com/onflow/fcl-dev-wallet/go/wallet"
-	"github.com/onflow/flow-emulator/server"
-	gatewayConfig "github.com/onflow/flow-evm-gateway/config"
 	"github.com/rs/zerolog"
 )
 
@@ -41,42 +36,99 @@ func isPortAvailable(port int) bool {
 	return true
 }
 
-// checkRequiredPorts validates that all required ports are available
-func checkRequiredPorts(cfg *config.Config) error {
-	if cfg.Network != "emulator" {
+// checkRequiredPorts validates that all required ports are available. With ports.auto a port
+// that is in use is replaced with a free one in cfg, the moved ports are returned for logging.
+func checkRequiredPorts(cfg *config.Config) ([]string, error) {
+	if !config.IsEmulator(cfg.Network) {
 		// Only check ports in emulator mode
-		return nil
+		return nil, nil
 	}
 
-	type portCheck struct {
-		port int
-		name string
+	ports := cfg.Ports.All()
+	taken := make(map[int]bool, len(ports))
+	for _, p := range ports {
+		taken[*p.Port] = true
 	}
 
-	portsToCheck := []portCheck{
-		{cfg.Ports.Emulator.GRPC, "Emulator gRPC"},
-		{cfg.Ports.Emulator.REST, "Emulator REST"},
-		{cfg.Ports.Emulator.Admin, "Emulator Admin"},
-		{cfg.Ports.Emulator.Debugger, "Emulator Debugger"},
-		{cfg.Ports.DevWallet, "Dev Wallet"},
-		{cfg.Ports.EVM.RPC, "EVM Gateway RPC"},
-		{cfg.Ports.EVM.Profiler, "EVM Gateway Profiler"},
-		{cfg.Ports.EVM.Metrics, "EVM Gateway Metrics"},
-	}
-
-	var unavailablePorts []string
-	for _, pc := range portsToCheck {
-		if !isPortAvailable(pc.port) {
-			unavailablePorts = append(unavailablePorts, fmt.Sprintf("%s (port %d)", pc.name, pc.port))
+	var unavailablePorts, moved []string
+	for _, p := range ports {
+		if isPortAvailable(*p.Port) {
+			continue
 		}
+		if !cfg.Ports.Auto {
+			unavailablePorts = append(unavailablePorts, fmt.Sprintf("%s (port %d)", p.Name, *p.Port))
+			continue
+		}
+		free, err := freePort(taken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find a free port for %s: %w", p.Name, err)
+		}
+		moved = append(moved, fmt.Sprintf("%s %d -> %d", p.Name, *p.Port, free))
+		*p.Port = free
+		taken[free] = true
 	}
 
 	if len(unavailablePorts) > 0 {
-		return fmt.Errorf("the following ports are already in use:\n  - %s\n\nPlease stop the services using these ports or configure different ports in your config file", 
+		return nil, fmt.Errorf("the following ports are already in use:\n  - %s\n\nPlease stop the services using these ports, configure different ports in your config file or set ports.auto: true",
 			joins(unavailablePorts, "\n  - "))
 	}
 
-	return nil
+	return moved, nil
+}
+
+// freePort asks the OS for a free port that is not already taken by another service
+func freePort(taken map[int]bool) (int, error) {
+	for i := 0; i < 10; i++ {
+		listener, err := net.Listen("tcp", ":0")
+		if err != nil {
+			return 0, err
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		_ = listener.Close()
+		if !taken[port] {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found")
+}
+
+// writeRuntimeFile tells other tools which ports the local services use, the returned func removes the file again
+func writeRuntimeFile(cfg *config.Config, logger zerolog.Logger) func() {
+	if !config.IsEmulator(cfg.Network) {
+		return func() {}
+	}
+	if err := config.WriteRuntime(config.RuntimeFile, config.NewRuntime(cfg)); err != nil {
+		logger.Warn().Err(err).Msg("Failed to write runtime file")
+		return func() {}
+	}
+	logger.Info().Str("file", config.RuntimeFile).Msg("Wrote the ports of the local services")
+	return func() { _ = config.RemoveRuntime(config.RuntimeFile, os.Getpid()) }
+}
+
+// openHistory stores what the views show in .aether/history, a sends them what was stored in earlier runs once it
+// has started. It returns the sink the services should send to and a func that closes the history.
+func openHistory(cfg *config.Config, a *aether.Aether, p *tea.Program, logger zerolog.Logger) (events.Sink, func()) {
+	if !cfg.UI.History.Persist {
+		return p, func() {}
+	}
+	h, err := aether.OpenHistory(cfg, &logger)
+	if err != nil {
+		logger.Warn().Err(err).Msg("History is not persisted")
+		return p, func() {}
+	}
+	a.History = h
+	return h.Sink(p), func() { _ = h.Close() }
+}
+
+// useRunningPorts makes a command talk to the services of the aether running in this folder, if any
+func useRunningPorts(cfg *config.Config) {
+	if !config.IsEmulator(cfg.Network) {
+		return
+	}
+	// A killed aether leaves its runtime file behind, its ports are not used anymore
+	if rt, err := config.ReadRuntime(config.RuntimeFile); err == nil && rt.Running() {
+		cfg.ApplyRuntime(rt)
+	}
 }
 
 // joins is a simple helper to join strings with a separator
@@ -91,12 +143,45 @@ func joins(strs []string, sep string) string {
 	return result
 }
 
+// writeCoverageOnExit writes the coverage report when aether stops, if the emulator collected coverage.
+// The result goes to stderr since the TUI and log sinks are gone by then.
+func writeCoverageOnExit(a *aether.Aether) {
+	if !a.CoverageEnabled() {
+		return
+	}
+	result, err := a.WriteCoverageReport()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write coverage report: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Coverage %s of %d contracts written to %s and %s\n", result.Percentage, result.Contracts, result.LCOVPath, result.JSONPath)
+}
+
+// subcommands are dispatched on the first argument, everything else starts the TUI
+var subcommands = map[string]func(args []string) int{
+	"ci":     ciCommand,
+	"commit": commitCommand,
+	"run":    runCommand,
+	"test":   testCommand,
+	"travel": travelCommand,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
 	// Parse command line flags
 	configPath := flag.String("config", "", "Path to configuration file")
 	network := flag.String("n", "", "Network to follow: emulator, testnet, or mainnet (overrides config)")
 	debugFlag := flag.Bool("debug", false, "Enable debug logging to aether-debug.log")
 	flag.BoolVar(debugFlag, "d", false, "Enable debug logging to aether-debug.log (shorthand)")
+	headless := flag.Bool("headless", false, "Run without the TUI and stream transactions and events as NDJSON to stdout")
+	coverage := flag.Bool("coverage", false, "Collect Cadence code coverage and write an LCOV and JSON report to flow.coverage.folder on exit")
+	startHeight := flag.Uint64("start-height", 0, "First block to index when following testnet or mainnet (overrides config)")
+	endHeight := flag.Uint64("end-height", 0, "Last block to index, aether stops indexing after it (overrides config)")
 	flag.Parse()
 
 	// Create debug logger if --debug/-d flag is set
@@ -136,6 +221,19 @@ func main() {
 	if *network != "" {
 		cfg.Network = *network
 	}
+	if *coverage {
+		cfg.Flow.Coverage.Enabled = true
+	}
+	if *startHeight != 0 {
+		cfg.Indexer.StartHeight = *startHeight
+	}
+	if *endHeight != 0 {
+		cfg.Indexer.EndHeight = *endHeight
+	}
+	if err := cfg.ValidateIndexRange(); err != nil {
+		fmt.Printf("Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Create logger with or without file output based on config
 	var logger zerolog.Logger
@@ -158,10 +256,8 @@ func main() {
 	logger = logger.Level(logs.ParseLogLevel(cfg.Logging.Level.Global))
 
 	// Create component-specific loggers with their configured levels
-	aetherLogger := logs.WithComponent(logger, "aether").Level(logs.ParseLogLevel(cfg.Logging.Level.Aether))
-	emulatorLogger := logs.WithComponent(logger, "emulator").Level(logs.ParseLogLevel(cfg.Logging.Level.Emulator))
-	walletLogger := logs.WithComponent(logger, "dev-wallet").Level(logs.ParseLogLevel(cfg.Logging.Level.DevWallet))
-	gatewayLogger := logs.WithComponent(logger, "evm-gateway").Level(logs.ParseLogLevel(cfg.Logging.Level.EVMGateway))
+	log := newLoggers(logger, cfg)
+	aetherLogger := log.aether
 
 	if cfg.Logging.File.Enabled {
 		aetherLogger.Info().Str("file", cfg.Logging.File.Path).Msg("Logging to file for debugging")
@@ -175,75 +271,41 @@ func main() {
 	}
 
 	// Check if required ports are available before starting services
-	if err := checkRequiredPorts(cfg); err != nil {
+	moved, err := checkRequiredPorts(cfg)
+	if err != nil {
 		fmt.Printf("\nError: %v\n\n", err)
 		os.Exit(1)
 	}
-
-	// Initialize components based on whether we're following a network or running locally
-	var emu *server.EmulatorServer
-	var dw *devWallet.Server
-	var gateway *flow.Gateway
-	var gatewayCfg gatewayConfig.Config
-	var emulatorReady chan struct{}
-	var frontendManager *frontend.FrontendManager
-
-	if cfg.Network == "emulator" {
-		// Local mode: start emulator, dev wallet, and EVM gateway
-		aetherLogger.Info().Msg("Initializing Flow emulator, dev wallet, and EVM gateway...")
-		var err2 error
-		emu, dw, err2 = flow.InitEmulator(&emulatorLogger, cfg)
-		if err2 != nil {
-			aetherLogger.Error().Err(err2).Msg("Failed to initialize Flow emulator & dev wallet")
-			panic(err2)
+	for _, m := range moved {
+		aetherLogger.Warn().Str("port", m).Msg("Port in use, using a free port instead")
+	}
+	// os.Exit skips deferred funcs, so the paths that exit remove the runtime file themselves
+	removeRuntimeFile := writeRuntimeFile(cfg, aetherLogger)
+	defer removeRuntimeFile()
+
+	if *headless {
+		if err := runHeadless(cfg, log, logWriter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			removeRuntimeFile()
+			os.Exit(1)
 		}
+		return
+	}
 
-		aetherLogger.Info().Msg("Initializing EVM gateway...")
-		var err3 error
-		gateway, gatewayCfg, err3 = flow.InitGateway(gatewayLogger, cfg)
-		if err3 != nil {
-			aetherLogger.Error().Err(err3).Msg("Failed to initialize EVM gateway")
-			panic(err3)
-		}
-		aetherLogger.Info().Msg("EVM gateway initialization complete")
-		aetherLogger.Info().Msg("All initialization complete")
-
-		// Channel to signal when emulator is ready
-		emulatorReady = make(chan struct{})
-
-		// Start emulator in background
-		go func() {
-			emulatorLogger.Info().Msg("Starting Flow emulator...")
-			go func() {
-				emu.Start()
-				emulatorLogger.Info().Msg("Emulator stopped")
-			}()
-
-			// Wait for emulator to start listening
-			time.Sleep(1 * time.Second)
-			emulatorLogger.Info().Msg("Emulator is ready")
-			close(emulatorReady)
-		}()
-
-		// Start dev wallet in background
-		go func() {
-			walletLogger.Info().Msg("Starting dev wallet...")
-			if err := dw.Start(); err != nil {
-				walletLogger.Error().Err(err).Msg("Dev wallet stopped with error")
-			}
-		}()
-	} else {
-		// Network mode: following testnet or mainnet
-		aetherLogger.Info().Str("network", cfg.Network).Msg("Following network - no local services will be started")
-		emulatorReady = make(chan struct{})
-		close(emulatorReady) // Immediately ready since we're not starting an emulator
+	// Initialize components based on whether we're following a network or running locally
+	svc, err := startServices(cfg, log)
+	if err != nil {
+		removeRuntimeFile()
+		panic(err)
 	}
 
 	a := aether.Aether{
-		Logger:  &aetherLogger,
-		FclCdc:  fclCdc,
-		Network: cfg.Network,
-		Config:  cfg,
+		Logger:   &aetherLogger,
+		FclCdc:   fclCdc,
+		Network:  cfg.Network,
+		Config:   cfg,
+		Emulator: svc.emulator(),
+		Services: svc.controller(),
 	}
 
 	// Create views externally for better composability
@@ -252,9 +314,10 @@ func main() {
 	eventsView := ui.NewEventsViewWithConfig(cfg, debugLogger)
 	runnerView := ui.NewRunnerViewWithConfig(cfg, debugLogger)
 	logsView := ui.NewLogsViewWithConfig(cfg, debugLogger)
+	profileView := ui.NewProfileViewWithConfig(cfg, debugLogger, &a)
 
 	// Create model with pre-created views using new tabbedtui package
-	tabs := []tabbedtui.TabbedModelPage{dashboardView, txView, eventsView, runnerView, logsView}
+	tabs := []tabbedtui.TabbedModelPage{dashboardView, txView, eventsView, runnerView, logsView, profileView}
 	model := tabbedtui.NewModel(tabs,
 		tabbedtui.WithStyles(ui.GetTabbedStyles()),
 	)
@@ -265,66 +328,42 @@ func main() {
 		tea.WithAltScreen(), // Use alternate screen buffer
 	)
 
-	// Start EVM gateway after emulator is ready (only in local mode)
-	if cfg.Network == "emulator" {
-		go func() {
-			<-emulatorReady // Wait for emulator to be ready
-			gatewayLogger.Info().Msg("Starting EVM gateway...")
-			gateway.Start(gatewayCfg)
-		}()
-	}
+	// Transactions, events and logs are kept in the history, so they survive a restart
+	sink, closeHistory := openHistory(cfg, &a, p, aetherLogger)
+
+	// Show the status of the local services on the dashboard
+	svc.watchHealth(p)
 
 	// Start frontend process if configured (after emulator is ready)
-	if cfg.FrontendCommand != "" {
-		go func() {
-			<-emulatorReady // Wait for emulator to be ready
-			frontendManager = frontend.NewFrontendManager(cfg.FrontendCommand, logger)
-			if err := frontendManager.Start(p); err != nil {
-				logger.Error().Err(err).Msg("Failed to start frontend process")
-			}
-		}()
-	}
+	svc.startFrontend(p)
 
 	// Start aether server after emulator is ready with tea program
 	go func() {
-		<-emulatorReady
+		<-svc.ready
 		aetherLogger.Info().Msg("Starting aether server")
-		if err := a.Start(p); err != nil {
+		if err := a.Start(sink); err != nil {
 			aetherLogger.Error().Err(err).Msg("Failed to start aether server")
 		}
 	}()
 
 	// Attach the Tea program to the log writer
 	// This will drain any buffered logs and start sending new logs to the UI
-	logWriter.AttachProgram(p)
+	logWriter.AttachSink(sink)
 
 	// Start the Bubble Tea program
 	if _, err := p.Run(); err != nil {
+		removeRuntimeFile()
 		logger.Fatal().Err(err).Msg("Failed to run Bubble Tea program")
 	}
 
 	aetherLogger.Info().Msg("Shutting down...")
+	writeCoverageOnExit(&a)
 
 	// Cleanup (deferred functions will run here)
 	logWriter.Close()
-	if cfg.Network == "emulator" {
-		// Only stop local services if they were started
-		gatewayLogger.Info().Msg("Stopping EVM gateway...")
-		gateway.Stop()
-		gatewayLogger.Info().Msg("EVM gateway stopped")
-		emulatorLogger.Info().Msg("Stopping emulator...")
-		emu.Stop()
-		walletLogger.Info().Msg("Stopping dev wallet...")
-		dw.Stop()
-	}
-	if cfg.FrontendCommand != "" {
-		if err := frontendManager.Stop(); err != nil {
-			aetherLogger.Error().Err(err).Msg("Failed to stop frontend process")
-		} else {
-			aetherLogger.Info().Msg("Frontend process stopped")
-		}
-	}
+	svc.stop()
 	aetherLogger.Info().Msg("Stopping aether server...")
 	a.Stop()
+	closeHistory()
 	aetherLogger.Info().Msg("All services stopped cleanly")
 }
@@ -24,32 +24,106 @@ func NewAccountRegistry(o *overflow.OverflowState) *AccountRegistry {
 		return registry
 	}
 	
-	// Get all accounts for the emulator network using flowkit
-	networkAccounts := o.State.AccountsForNetwork(config.EmulatorNetwork)
-	if networkAccounts == nil {
-		return registry
+	// Get all accounts for the network overflow runs against using flowkit.
+	// When forking mainnet or testnet this is the forked network, so its accounts and aliases are used.
+	networkName := config.EmulatorNetwork.Name
+	if o.Network.Name != "" {
+		networkName = o.Network.Name
+	}
+	
+	networkAccounts := o.State.AccountsForNetwork(config.Network{Name: networkName})
+	if networkAccounts != nil {
+		for _, account := range *networkAccounts {
+			// Strip "<network>-" prefix if present
+			friendlyName := strings.TrimPrefix(account.Name, networkName+"-")
+			
+			// Override "account" to "service-account" for clarity
+			if friendlyName == "account" {
+				friendlyName = "service-account"
+			}
+			
+			// All Flow addresses should have 0x prefix and lowercase
+			// (Flow addresses are case-insensitive but we normalize to lowercase)
+			normalizedAddr := strings.ToLower(normalizeAddress(account.Address.String()))
+			
+			// Store with 0x prefix and lowercase
+			registry.addressToName[normalizedAddr] = friendlyName
+		}
 	}
 	
-	for _, account := range *networkAccounts {
-		// Strip "emulator-" prefix if present
-		friendlyName := strings.TrimPrefix(account.Name, "emulator-")
-		
-		// Override "account" to "service-account" for clarity
-		if friendlyName == "account" {
-			friendlyName = "service-account"
+	// A forked network also has accounts and contracts that are not deployed to by flow.json,
+	// name them as well unless an account above already claimed the address
+	if networkName != config.EmulatorNetwork.Name {
+		// Accounts without deployments are only named after their network, e.g. mainnet-admin
+		for _, account := range *o.State.Accounts() {
+			if !strings.HasPrefix(account.Name, networkName+"-") {
+				continue
+			}
+			registry.addName(strings.TrimPrefix(account.Name, networkName+"-"), account.Address.String())
+		}
+		if contracts := o.State.Contracts(); contracts != nil {
+			for _, contract := range *contracts {
+				if alias := contract.Aliases.ByNetwork(networkName); alias != nil {
+					registry.addName(contract.Name, alias.Address.String())
+				}
+			}
+		}
+		if dependencies := o.State.Dependencies(); dependencies != nil {
+			for _, dependency := range *dependencies {
+				if alias := dependency.Aliases.ByNetwork(networkName); alias != nil {
+					registry.addName(dependency.Name, alias.Address.String())
+				}
+			}
 		}
-		
-		// All Flow addresses should have 0x prefix and lowercase
-		// (Flow addresses are case-insensitive but we normalize to lowercase)
-		normalizedAddr := strings.ToLower(normalizeAddress(account.Address.String()))
-		
-		// Store with 0x prefix and lowercase
-		registry.addressToName[normalizedAddr] = friendlyName
 	}
 	
 	return registry
 }
 
+// addName registers a name for an address, keeping any name already registered for it
+func (r *AccountRegistry) addName(name, address string) {
+	normalizedAddr := strings.ToLower(normalizeAddress(address))
+	if _, exists := r.addressToName[normalizedAddr]; !exists {
+		r.addressToName[normalizedAddr] = name
+	}
+}
+
+// AddEVMAccount names an EVM address, e.g. a prefunded EOA from evm.accounts shown as alice-evm
+func (r *AccountRegistry) AddEVMAccount(name, address string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addName(name+"-evm", address)
+}
+
+// AddCOA names the Cadence-Owned Account of a Flow account, e.g. alice-coa
+func (r *AccountRegistry) AddCOA(name, address string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addName(name+"-coa", address)
+}
+
+// FlowAccounts returns the named Flow accounts keyed by address, EVM addresses are left out
+func (r *AccountRegistry) FlowAccounts() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make(map[string]string)
+	for address, name := range r.addressToName {
+		// Flow addresses are 8 bytes, EVM addresses 20
+		if len(address) == 2+16 {
+			accounts[address] = name
+		}
+	}
+	return accounts
+}
+
+// AddEVMContract names a contract deployed from evm.contracts
+func (r *AccountRegistry) AddEVMContract(name, address string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addName(name, address)
+}
+
 // DebugDump logs all registered accounts for debugging
 func (r *AccountRegistry) DebugDump() map[string]string {
 	r.mu.RLock()
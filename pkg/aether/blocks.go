@@ -0,0 +1,53 @@
+package aether
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxCommitBlocks bounds how many blocks can be committed in one call
+const maxCommitBlocks = 1000
+
+// CommittedBlock is the last block committed by CommitBlocks or AdvanceTime
+type CommittedBlock struct {
+	Height    uint64
+	Timestamp time.Time
+}
+
+// ManualBlocks reports whether the local emulator only commits blocks on demand (flow.manual_blocks)
+func (a *Aether) ManualBlocks() bool {
+	return a.Emulator != nil && a.Config != nil && a.Config.Flow.ManualBlocks
+}
+
+// enterManualBlocks stops the emulator from committing a block per transaction.
+// It is called once the local setup is done, since deploying contracts and running
+// init transactions waits for every transaction to be sealed.
+func (a *Aether) enterManualBlocks() {
+	if !a.ManualBlocks() {
+		return
+	}
+	a.Emulator.DisableAutoMine()
+	a.Logger.Info().Msg("Manual block production, transactions wait in the pending block until a block is committed")
+}
+
+// CommitBlocks executes the pending transactions and commits count blocks, the first block gets
+// all pending transactions and the rest are empty. It returns the last committed block.
+func (a *Aether) CommitBlocks(count int) (CommittedBlock, error) {
+	var last CommittedBlock
+	if a.Emulator == nil {
+		return last, fmt.Errorf("blocks can only be committed on the local emulator")
+	}
+	if count < 1 || count > maxCommitBlocks {
+		return last, fmt.Errorf("invalid block count %d: must be between 1 and %d", count, maxCommitBlocks)
+	}
+
+	for i := 0; i < count; i++ {
+		block, results, err := a.Emulator.ExecuteAndCommitBlock()
+		if err != nil {
+			return last, fmt.Errorf("failed to commit block: %w", err)
+		}
+		last = CommittedBlock{Height: block.Height, Timestamp: time.UnixMilli(int64(block.Timestamp))}
+		a.Logger.Info().Uint64("height", last.Height).Int("txCount", len(results)).Msg("Committed block")
+	}
+	return last, nil
+}
@@ -0,0 +1,69 @@
+package aether
+
+import (
+	"os"
+	"time"
+
+	"github.com/bjartek/aether/pkg/flow"
+)
+
+// checkpointInterval is how often the checkpoint is written while blocks are indexed
+const checkpointInterval = time.Second
+
+// resumeHeight returns the height to stream after from the checkpoint of the previous run on this network
+func (a *Aether) resumeHeight(latest uint64) (uint64, bool) {
+	cp, err := flow.ReadCheckpoint(a.checkpointFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			a.Logger.Warn().Err(err).Msg("Failed to read indexer checkpoint, starting from the latest block")
+		}
+		return 0, false
+	}
+
+	height, skipped, ok := flow.ResumeHeight(cp.Height, latest, a.Config.Indexer.MaxCatchUp)
+	if !ok {
+		a.Logger.Warn().
+			Uint64("checkpoint", cp.Height).
+			Uint64("latest", latest).
+			Msg("Indexer checkpoint is ahead of the network, starting from the latest block")
+		return 0, false
+	}
+	if skipped > 0 {
+		a.Logger.Warn().
+			Uint64("checkpoint", cp.Height).
+			Uint64("skipped", skipped).
+			Uint64("maxCatchUp", a.Config.Indexer.MaxCatchUp).
+			Msg("Indexer checkpoint is older than indexer.max_catch_up, skipping blocks")
+	}
+	a.Logger.Info().
+		Uint64("checkpoint", cp.Height).
+		Uint64("resumeAfter", height).
+		Uint64("latest", latest).
+		Msg("Resuming indexer from checkpoint")
+	return height, true
+}
+
+// saveCheckpoint records height as fully processed. The file is written at most every checkpointInterval
+// unless force is set, e.g. when aether stops.
+func (a *Aether) saveCheckpoint(height uint64, force bool) {
+	if a.checkpointFile == "" {
+		return
+	}
+
+	a.mu.Lock()
+	if height > a.checkpointHeight {
+		a.checkpointHeight = height
+	}
+	height = a.checkpointHeight
+	if height == 0 || (!force && time.Since(a.checkpointSaved) < checkpointInterval) {
+		a.mu.Unlock()
+		return
+	}
+	a.checkpointSaved = time.Now()
+	a.mu.Unlock()
+
+	cp := flow.Checkpoint{Network: a.Network, Height: height, Updated: time.Now().UTC()}
+	if err := flow.WriteCheckpoint(a.checkpointFile, cp); err != nil {
+		a.Logger.Warn().Err(err).Msg("Failed to write indexer checkpoint")
+	}
+}
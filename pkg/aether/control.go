@@ -0,0 +1,158 @@
+package aether
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bjartek/aether/pkg/config"
+)
+
+// Control API paths
+const (
+	// ControlCommitPath commits blocks on the local emulator, e.g. POST /blocks/commit?count=5
+	ControlCommitPath = "/blocks/commit"
+	// ControlAdvanceTimePath moves the emulator time forward and commits a block, e.g. POST /time/advance?by=24h
+	ControlAdvanceTimePath = "/time/advance"
+)
+
+// BlockResponse is the JSON response of ControlCommitPath and ControlAdvanceTimePath with the last committed block
+type BlockResponse struct {
+	Height    uint64    `json:"height"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// controlError is the JSON response of a failed control request
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// startControlServer serves the control API used by the aether CLI on localhost.
+// It is only started for the local emulator and stopped together with everything else in Start.
+func (a *Aether) startControlServer() {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ControlCommitPath, a.handleCommit)
+	mux.HandleFunc(ControlAdvanceTimePath, a.handleAdvanceTime)
+
+	addr := fmt.Sprintf("127.0.0.1:%d", a.Config.Ports.Control)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		a.Logger.Error().Err(err).Str("addr", addr).Msg("Failed to start control API")
+		return
+	}
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.Logger.Warn().Err(err).Msg("Control API stopped")
+		}
+	}()
+	go func() {
+		<-a.ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}()
+	a.Logger.Info().Str("addr", addr).Msg("Control API listening")
+}
+
+func (a *Aether) handleCommit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeControlJSON(w, http.StatusMethodNotAllowed, controlError{Error: "use POST"})
+		return
+	}
+
+	count := 1
+	if c := r.URL.Query().Get("count"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			writeControlJSON(w, http.StatusBadRequest, controlError{Error: fmt.Sprintf("invalid count %q", c)})
+			return
+		}
+		count = n
+	}
+
+	block, err := a.CommitBlocks(count)
+	writeBlockResponse(w, block, err)
+}
+
+func (a *Aether) handleAdvanceTime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeControlJSON(w, http.StatusMethodNotAllowed, controlError{Error: "use POST"})
+		return
+	}
+
+	d, err := ParseTravelDuration(r.URL.Query().Get("by"))
+	if err != nil {
+		writeControlJSON(w, http.StatusBadRequest, controlError{Error: err.Error()})
+		return
+	}
+
+	block, err := a.AdvanceTime(d)
+	writeBlockResponse(w, block, err)
+}
+
+func writeBlockResponse(w http.ResponseWriter, block CommittedBlock, err error) {
+	if err != nil {
+		writeControlJSON(w, http.StatusBadRequest, controlError{Error: err.Error()})
+		return
+	}
+	writeControlJSON(w, http.StatusOK, BlockResponse{Height: block.Height, Timestamp: block.Timestamp})
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// ControlClient talks to the control API of a running aether
+type ControlClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewControlClient creates a client for the control API on the configured port
+func NewControlClient(cfg *config.Config) *ControlClient {
+	return &ControlClient{
+		baseURL: fmt.Sprintf("http://127.0.0.1:%d", cfg.Ports.Control),
+		http:    &http.Client{Timeout: time.Minute},
+	}
+}
+
+// CommitBlocks commits count blocks and returns the last one
+func (c *ControlClient) CommitBlocks(count int) (BlockResponse, error) {
+	var res BlockResponse
+	err := c.post(ControlCommitPath, url.Values{"count": {strconv.Itoa(count)}}, &res)
+	return res, err
+}
+
+// AdvanceTime moves the emulator time forward by, e.g. 24h or 7d, and returns the block committed with the new time
+func (c *ControlClient) AdvanceTime(by string) (BlockResponse, error) {
+	var res BlockResponse
+	err := c.post(ControlAdvanceTimePath, url.Values{"by": {by}}, &res)
+	return res, err
+}
+
+func (c *ControlClient) post(path string, query url.Values, out any) error {
+	resp, err := c.http.Post(c.baseURL+path+"?"+query.Encode(), "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to reach aether, is it running with the local emulator? %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var ce controlError
+		if err := json.NewDecoder(resp.Body).Decode(&ce); err != nil || ce.Error == "" {
+			return fmt.Errorf("aether returned %s", resp.Status)
+		}
+		return errors.New(ce.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,138 @@
+package aether
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/bjartek/aether/pkg/flow"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/runtime"
+)
+
+const (
+	coverageLCOVFile = "coverage.lcov"
+	coverageJSONFile = "coverage.json"
+)
+
+// CoverageResult describes a written coverage report
+type CoverageResult struct {
+	LCOVPath   string
+	JSONPath   string
+	Contracts  int    // Number of contracts in the report
+	Percentage string // Line coverage over all contracts, e.g. "72.5%"
+}
+
+// CoverageEnabled reports whether the local emulator collects code coverage
+func (a *Aether) CoverageEnabled() bool {
+	return a.Emulator != nil && a.Emulator.CoverageReport() != nil
+}
+
+// WriteCoverageReport writes an LCOV and a JSON report with the line coverage of the contracts deployed
+// from flow.json to flow.coverage.folder. Everything executed so far counts, i.e. init transactions,
+// runner executions and transactions sent by the frontend.
+func (a *Aether) WriteCoverageReport() (CoverageResult, error) {
+	if !a.CoverageEnabled() {
+		return CoverageResult{}, fmt.Errorf("coverage is not enabled, set flow.coverage.enabled or start with -coverage")
+	}
+	if a.Overflow == nil {
+		return CoverageResult{}, fmt.Errorf("contracts are not deployed yet")
+	}
+
+	report, err := a.contractCoverage()
+	if err != nil {
+		return CoverageResult{}, fmt.Errorf("failed to read coverage: %w", err)
+	}
+
+	lcov, err := report.MarshalLCOV()
+	if err != nil {
+		return CoverageResult{}, fmt.Errorf("failed to create LCOV report: %w", err)
+	}
+	jsonReport, err := report.MarshalJSON()
+	if err != nil {
+		return CoverageResult{}, fmt.Errorf("failed to create JSON report: %w", err)
+	}
+
+	folder := a.Config.Flow.Coverage.Folder
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		return CoverageResult{}, fmt.Errorf("failed to create coverage folder: %w", err)
+	}
+
+	result := CoverageResult{
+		LCOVPath:   filepath.Join(folder, coverageLCOVFile),
+		JSONPath:   filepath.Join(folder, coverageJSONFile),
+		Contracts:  len(report.Coverage),
+		Percentage: report.Percentage(),
+	}
+	if err := os.WriteFile(result.LCOVPath, lcov, 0o644); err != nil {
+		return CoverageResult{}, fmt.Errorf("failed to write LCOV report: %w", err)
+	}
+	if err := os.WriteFile(result.JSONPath, jsonReport, 0o644); err != nil {
+		return CoverageResult{}, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+
+	a.Logger.Info().
+		Str("lcov", result.LCOVPath).
+		Str("json", result.JSONPath).
+		Int("contracts", result.Contracts).
+		Str("coverage", result.Percentage).
+		Msg("Wrote coverage report")
+	return result, nil
+}
+
+// contractCoverage copies the coverage of the contracts deployed from flow.json out of the emulator report,
+// leaving out system contracts, scripts and transactions. The LCOV source files point to the contract files.
+func (a *Aether) contractCoverage() (*runtime.CoverageReport, error) {
+	o := a.Overflow
+
+	sources := map[string]string{}
+	for _, deployment := range o.State.Deployments().ByNetwork(o.Network.Name) {
+		for _, c := range deployment.Contracts {
+			contract, err := o.State.Contracts().ByName(c.Name)
+			if err != nil || contract.Location == "" {
+				continue
+			}
+			sources[c.Name] = contract.Location
+		}
+	}
+
+	all := a.Emulator.CoverageReport()
+	report := runtime.NewCoverageReport()
+	report.WithLocationMappings(sources)
+
+	// Executions write to the line hits of the emulator report, so they are copied while none run
+	err := a.withEmulatorPaused(func() {
+		for location, coverage := range all.Coverage {
+			addressLocation, ok := location.(common.AddressLocation)
+			if !ok {
+				continue
+			}
+			if _, deployed := sources[addressLocation.Name]; !deployed {
+				continue
+			}
+			report.Coverage[location] = &runtime.LocationCoverage{
+				LineHits:   maps.Clone(coverage.LineHits),
+				Statements: coverage.Statements,
+			}
+			report.Locations[location] = struct{}{}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// withEmulatorPaused runs fn while the emulator executes no transactions or scripts. fn is not run if the
+// emulator can not be paused. fn must not call the emulator, it would wait for itself.
+func (a *Aether) withEmulatorPaused(fn func()) error {
+	lock, err := flow.EmulatorLock(a.Emulator)
+	if err != nil {
+		return err
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	fn()
+	return nil
+}
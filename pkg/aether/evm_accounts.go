@@ -0,0 +1,67 @@
+package aether
+
+import (
+	"fmt"
+
+	"github.com/bjartek/aether/pkg/flow"
+	"github.com/bjartek/overflow/v2"
+)
+
+// registerEVMAccounts names the accounts in evm.accounts in the account registry
+func (a *Aether) registerEVMAccounts() error {
+	accounts, err := flow.ResolveEVMAccounts(a.Config)
+	if err != nil {
+		return err
+	}
+	a.evmAccounts = accounts
+	for _, account := range accounts {
+		a.AccountRegistry.AddEVMAccount(account.Name, account.Address.Hex())
+	}
+	return nil
+}
+
+// registerCOAs names the Cadence-Owned Accounts of the named Flow accounts, so EVM calls made through
+// them show who made them. It is safe to call again when new COAs are created.
+func (a *Aether) registerCOAs(o *overflow.OverflowState) {
+	if a.AccountRegistry == nil {
+		return
+	}
+	accounts := a.AccountRegistry.FlowAccounts()
+	addresses := make([]string, 0, len(accounts))
+	for address := range accounts {
+		addresses = append(addresses, address)
+	}
+
+	coas, err := flow.ResolveCOAs(o, a.Config, addresses)
+	if err != nil {
+		a.Logger.Warn().Err(err).Msg("Failed to look up Cadence-Owned Accounts")
+		return
+	}
+	for address, coa := range coas {
+		a.AccountRegistry.AddCOA(accounts[address], coa)
+		a.Logger.Debug().Str("account", accounts[address]).Str("coa", coa).Msg("Registered Cadence-Owned Account")
+	}
+}
+
+// fundEVMAccounts funds the accounts in evm.accounts with FLOW, the EVM counterpart of flow.new_user_balance
+func (a *Aether) fundEVMAccounts(o *overflow.OverflowState) error {
+	if len(a.evmAccounts) == 0 {
+		return nil
+	}
+	if !a.Config.Flow.Emulator.SetupEVMEnabled {
+		a.Logger.Warn().Msg("EVM setup is disabled in the emulator - not funding evm.accounts")
+		return nil
+	}
+
+	for _, account := range a.evmAccounts {
+		if err := flow.FundEVMAccount(o, a.Config, account); err != nil {
+			return fmt.Errorf("failed to fund evm account '%s': %w", account.Name, err)
+		}
+		a.Logger.Info().
+			Str("name", account.Name).
+			Str("address", account.Address.Hex()).
+			Float64("balance", account.Balance).
+			Msg("Funded EVM account")
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package aether
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bjartek/aether/pkg/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// gatewayReadyTimeout is how long the deployment waits for the EVM gateway to answer
+	gatewayReadyTimeout = 2 * time.Minute
+	// receiptTimeout is how long the deployment waits for the receipt of a deploy transaction
+	receiptTimeout = time.Minute
+)
+
+// EVMDeployment is a contract from evm.contracts deployed into the EVM
+type EVMDeployment struct {
+	Address  string `json:"address"`
+	TxHash   string `json:"txHash"`
+	Deployer string `json:"deployer"`
+	Artifact string `json:"artifact"`
+}
+
+// evmDeploymentsFile is the content of evm.deployments_file, read by frontends to find the contracts
+type evmDeploymentsFile struct {
+	ChainID   uint64                   `json:"chainId"`
+	RPC       string                   `json:"rpc"`
+	Contracts map[string]EVMDeployment `json:"contracts"`
+}
+
+// deployEVMContracts deploys the contracts in evm.contracts in order through the EVM gateway and
+// writes their addresses to evm.deployments_file
+func (a *Aether) deployEVMContracts(ctx context.Context) error {
+	contracts := a.Config.EVM.Contracts
+	if len(contracts) == 0 {
+		return nil
+	}
+	if !a.Config.Flow.Emulator.SetupEVMEnabled {
+		a.Logger.Warn().Msg("EVM setup is disabled in the emulator - not deploying evm.contracts")
+		return nil
+	}
+
+	url := fmt.Sprintf("http://127.0.0.1:%d", a.Config.Ports.EVM.RPC)
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the EVM gateway: %w", err)
+	}
+	defer client.Close()
+
+	if err := waitForGateway(ctx, client); err != nil {
+		return err
+	}
+
+	// Address arguments can name an evm account or a contract deployed before it
+	addresses := make(map[string]common.Address)
+	for _, account := range a.evmAccounts {
+		addresses[account.Name] = account.Address
+	}
+	resolve := func(name string) (common.Address, bool) {
+		address, ok := addresses[name]
+		return address, ok
+	}
+
+	deployments := make(map[string]EVMDeployment, len(contracts))
+	for _, contract := range contracts {
+		artifact, err := evm.LoadArtifact(contract.Artifact)
+		if err != nil {
+			return fmt.Errorf("evm contract '%s': %w", contract.Name, err)
+		}
+		data, err := artifact.DeployData(contract.Args, resolve)
+		if err != nil {
+			return fmt.Errorf("evm contract '%s': %w", contract.Name, err)
+		}
+
+		deployer, key, err := a.evmDeployer(contract.Deployer)
+		if err != nil {
+			return fmt.Errorf("evm contract '%s': %w", contract.Name, err)
+		}
+
+		address, txHash, err := a.deployEVMContract(ctx, client, key, data)
+		if err != nil {
+			return fmt.Errorf("failed to deploy evm contract '%s': %w", contract.Name, err)
+		}
+
+		addresses[contract.Name] = address
+		deployments[contract.Name] = EVMDeployment{
+			Address:  address.Hex(),
+			TxHash:   txHash.Hex(),
+			Deployer: deployer,
+			Artifact: contract.Artifact,
+		}
+		a.AccountRegistry.AddEVMContract(contract.Name, address.Hex())
+		a.Logger.Info().
+			Str("name", contract.Name).
+			Str("address", address.Hex()).
+			Str("deployer", deployer).
+			Msg("Deployed EVM contract")
+	}
+
+	return writeEVMDeployments(a.Config.EVM.DeploymentsFile, evmDeploymentsFile{
+		ChainID:   a.Config.EVM.ChainID,
+		RPC:       url,
+		Contracts: deployments,
+	})
+}
+
+// evmDeployer returns the name and key of the account that deploys a contract, an empty name is the
+// first evm account or the gateway wallet when there are none
+func (a *Aether) evmDeployer(name string) (string, *ecdsa.PrivateKey, error) {
+	for _, account := range a.evmAccounts {
+		if name == "" || account.Name == name {
+			return account.Name, account.Key, nil
+		}
+	}
+	if name != "" {
+		return "", nil, fmt.Errorf("deployer '%s' is not in evm.accounts", name)
+	}
+	key, err := evm.ParsePrivateKey(a.Config.EVM.WalletKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("evm.wallet_key: %w", err)
+	}
+	return "wallet", key, nil
+}
+
+// deployEVMContract sends a contract creation transaction signed by key and waits for its receipt
+func (a *Aether) deployEVMContract(ctx context.Context, client *rpc.Client, key *ecdsa.PrivateKey, data []byte) (common.Address, common.Hash, error) {
+	from := gethCrypto.PubkeyToAddress(key.PublicKey)
+
+	var nonce hexutil.Uint64
+	if err := client.CallContext(ctx, &nonce, "eth_getTransactionCount", from, "pending"); err != nil {
+		return common.Address{}, common.Hash{}, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	var gas hexutil.Uint64
+	call := map[string]any{"from": from, "data": hexutil.Bytes(data)}
+	if err := client.CallContext(ctx, &gas, "eth_estimateGas", call); err != nil {
+		return common.Address{}, common.Hash{}, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	chainID := new(big.Int).SetUint64(a.Config.EVM.ChainID)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.LegacyTx{
+		Nonce:    uint64(nonce),
+		GasPrice: new(big.Int).SetUint64(a.Config.EVM.GasPrice),
+		Gas:      uint64(gas),
+		Data:     data,
+	})
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+
+	var txHash common.Hash
+	if err := client.CallContext(ctx, &txHash, "eth_sendRawTransaction", hexutil.Bytes(raw)); err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+
+	address, err := waitForContractAddress(ctx, client, txHash)
+	return address, txHash, err
+}
+
+// waitForGateway waits until the EVM gateway answers requests
+func waitForGateway(ctx context.Context, client *rpc.Client) error {
+	ctx, cancel := context.WithTimeout(ctx, gatewayReadyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		var chainID hexutil.Uint64
+		if err := client.CallContext(ctx, &chainID, "eth_chainId"); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("EVM gateway did not become ready: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForContractAddress polls the receipt of a contract creation until the gateway has indexed it
+func waitForContractAddress(ctx context.Context, client *rpc.Client, txHash common.Hash) (common.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, receiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		var receipt *struct {
+			Status          hexutil.Uint64  `json:"status"`
+			ContractAddress *common.Address `json:"contractAddress"`
+		}
+		if err := client.CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+			return common.Address{}, err
+		}
+		if receipt != nil {
+			if receipt.Status == 0 {
+				return common.Address{}, fmt.Errorf("transaction %s reverted", txHash.Hex())
+			}
+			if receipt.ContractAddress == nil {
+				return common.Address{}, fmt.Errorf("transaction %s did not create a contract", txHash.Hex())
+			}
+			return *receipt.ContractAddress, nil
+		}
+		select {
+		case <-ctx.Done():
+			return common.Address{}, fmt.Errorf("no receipt for transaction %s: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// registerEVMDeployments names the contracts of a previous run, used when the emulator state is restored
+func (a *Aether) registerEVMDeployments() {
+	if len(a.Config.EVM.Contracts) == 0 {
+		return
+	}
+	data, err := os.ReadFile(a.Config.EVM.DeploymentsFile)
+	if err != nil {
+		a.Logger.Warn().Err(err).Msg("No EVM deployments from a previous run, the contracts are shown by address")
+		return
+	}
+	var file evmDeploymentsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		a.Logger.Warn().Err(err).Str("file", a.Config.EVM.DeploymentsFile).Msg("Invalid EVM deployments file")
+		return
+	}
+	for name, deployment := range file.Contracts {
+		a.AccountRegistry.AddEVMContract(name, deployment.Address)
+	}
+}
+
+// writeEVMDeployments writes the deployed contracts to path, creating the folder if needed
+func writeEVMDeployments(path string, file evmDeploymentsFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create deployments folder: %w", err)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write evm deployments: %w", err)
+	}
+	return nil
+}
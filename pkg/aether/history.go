@@ -0,0 +1,518 @@
+package aether
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/events"
+	"github.com/bjartek/aether/pkg/history"
+	"github.com/bjartek/aether/pkg/logs"
+	"github.com/bjartek/overflow/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
+)
+
+// historyLoadSize is how many of the newest transactions, events and log lines are shown again on start,
+// older ones are paged in by the views when scrolling past them
+const historyLoadSize = 500
+
+// History keeps the transactions, events and log lines sent to the views in .aether/history/<network>,
+// so they are still there after aether is restarted
+type History struct {
+	entries   *history.Store
+	logger    *zerolog.Logger
+	maxStored int
+	openedAt  uint64 // Newest entry when the history was opened, Load reads up to it
+
+	mu      sync.Mutex // Held while recording a message
+	closed  bool
+	sources map[string]TransactionSourceMsg // Source files of transactions that are not indexed yet
+	chain   *chainBlock                     // Nil until a transaction is stored
+	added   map[history.Kind]int            // Entries added since the last prune
+}
+
+// chainBlock is the first block a stored transaction was indexed from. If the chain no longer has it, e.g. when
+// the emulator started over without persisted state, the stored transactions are of a chain that is gone.
+type chainBlock struct {
+	Height uint64 `json:"height"`
+	ID     string `json:"id"`
+}
+
+// HistoryLoadedMsg is sent once the indexer is about to start with the newest entries of the history, oldest first.
+// Views use History to page in older entries.
+type HistoryLoadedMsg struct {
+	History      *History
+	Transactions []BlockTransactionMsg
+	Events       []BlockEventMsg
+	Logs         []logs.LogLineMsg
+}
+
+// OpenHistory opens the history of the network in cfg and prunes it to ui.history.max_stored entries
+func OpenHistory(cfg *config.Config, logger *zerolog.Logger) (*History, error) {
+	store, err := history.Open(history.Dir(cfg.Network))
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range []history.Kind{history.Transactions, history.Events, history.Logs} {
+		if err := store.Prune(kind, cfg.UI.History.MaxStored); err != nil {
+			_ = store.Close()
+			return nil, fmt.Errorf("failed to prune history: %w", err)
+		}
+	}
+
+	h := &History{
+		entries:   store,
+		logger:    logger,
+		maxStored: cfg.UI.History.MaxStored,
+		openedAt:  store.Seq(),
+		sources:   make(map[string]TransactionSourceMsg),
+		added:     make(map[history.Kind]int),
+	}
+	value, ok, err := store.Get(history.Chain, "block")
+	if err == nil && ok {
+		err = json.Unmarshal(value, &h.chain)
+	}
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("failed to read the chain of the history: %w", err)
+	}
+	return h, nil
+}
+
+// loadHistory sends the stored history to the views. On the local emulator the stored transactions and events
+// are removed first if they are of a chain that is gone, their heights would be shown twice.
+func (a *Aether) loadHistory(ctx context.Context) {
+	h := a.History
+	if h == nil || a.sink == nil {
+		return
+	}
+
+	if a.isLocal() {
+		cleared, err := h.checkChain(func(height uint64) (string, error) {
+			block, err := a.Overflow.GetBlockAtHeight(ctx, height)
+			if err != nil {
+				return "", err
+			}
+			return block.ID.String(), nil
+		})
+		if err != nil {
+			a.Logger.Warn().Err(err).Msg("Failed to remove the transactions of an earlier chain from the history")
+		} else if cleared {
+			a.Logger.Info().Msg("The emulator chain has changed, removed its transactions and events from the history")
+		}
+	}
+
+	loaded, err := h.Load()
+	if err != nil {
+		a.Logger.Warn().Err(err).Msg("Failed to load history")
+	}
+	a.sink.Send(loaded)
+}
+
+// Close closes the history, messages sent to the sink after it are no longer stored
+func (h *History) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	return h.entries.Close()
+}
+
+// Sink stores the transactions, events and log lines sent to it before passing every message on to next,
+// with HistorySeq set to where it was stored
+func (h *History) Sink(next events.Sink) events.Sink {
+	return events.SinkFunc(func(msg tea.Msg) {
+		next.Send(h.record(msg))
+	})
+}
+
+// Load reads the newest entries of every kind that were stored before the history was opened,
+// the views got the ones stored since then already
+func (h *History) Load() (HistoryLoadedMsg, error) {
+	msg := HistoryLoadedMsg{History: h}
+	before := h.openedAt + 1
+	var err error
+	if msg.Transactions, err = h.Transactions(before, historyLoadSize); err != nil {
+		return msg, err
+	}
+	if msg.Events, err = h.Events(before, historyLoadSize); err != nil {
+		return msg, err
+	}
+	if msg.Logs, err = h.Logs(before, historyLoadSize); err != nil {
+		return msg, err
+	}
+	return msg, nil
+}
+
+// Transactions returns up to limit stored transactions from before seq, or the newest ones if seq is 0. Oldest first.
+func (h *History) Transactions(seq uint64, limit int) ([]BlockTransactionMsg, error) {
+	entries, err := h.entries.Before(history.Transactions, seq, limit)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]BlockTransactionMsg, 0, len(entries))
+	for _, entry := range entries {
+		var stored storedTransaction
+		if err := unmarshalWithNumbers(entry.Value, &stored); err != nil {
+			return nil, fmt.Errorf("invalid transaction in history: %w", err)
+		}
+		transactions = append(transactions, BlockTransactionMsg{TransactionData: stored.transactionData(), HistorySeq: entry.Seq})
+	}
+	return transactions, nil
+}
+
+// Events returns up to limit stored events from before seq, or the newest ones if seq is 0. Oldest first.
+func (h *History) Events(seq uint64, limit int) ([]BlockEventMsg, error) {
+	entries, err := h.entries.Before(history.Events, seq, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]BlockEventMsg, 0, len(entries))
+	for _, entry := range entries {
+		var event EventData
+		if err := unmarshalWithNumbers(entry.Value, &event); err != nil {
+			return nil, fmt.Errorf("invalid event in history: %w", err)
+		}
+		event.Fields = restoreNumbers(event.Fields).(map[string]interface{})
+		result = append(result, BlockEventMsg{EventData: event, HistorySeq: entry.Seq})
+	}
+	return result, nil
+}
+
+// Logs returns up to limit stored log lines from before seq, or the newest ones if seq is 0. Oldest first.
+func (h *History) Logs(seq uint64, limit int) ([]logs.LogLineMsg, error) {
+	entries, err := h.entries.Before(history.Logs, seq, limit)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]logs.LogLineMsg, len(entries))
+	for i, entry := range entries {
+		lines[i] = logs.LogLineMsg{Line: string(entry.Value), HistorySeq: entry.Seq}
+	}
+	return lines, nil
+}
+
+// record stores msg if it is shown in one of the views and returns it with its place in the history
+func (h *History) record(msg tea.Msg) tea.Msg {
+	h.mu.Lock()
+	msg, err := h.store(msg)
+	h.mu.Unlock()
+
+	// Logged after unlocking, the log line comes back through the sink
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("Failed to store history")
+	}
+	return msg
+}
+
+// store stores msg, h.mu must be held
+func (h *History) store(msg tea.Msg) (tea.Msg, error) {
+	if h.closed {
+		return msg, nil
+	}
+	var err error
+	switch m := msg.(type) {
+	case BlockTransactionMsg:
+		if h.chain == nil {
+			if err := h.setChain(m.TransactionData.BlockHeight, m.TransactionData.BlockID); err != nil {
+				return m, err
+			}
+		}
+		if m.HistorySeq, err = h.addTransaction(m.TransactionData); err != nil {
+			return m, err
+		}
+		return m, h.pruneAfterAdd(history.Transactions)
+	case BlockEventMsg:
+		if m.HistorySeq, err = h.addEvent(m.EventData); err != nil {
+			return m, err
+		}
+		return m, h.pruneAfterAdd(history.Events)
+	case TransactionSourceMsg:
+		return msg, h.setSource(m)
+	case IndexerResetMsg:
+		// The views drop what they have, so do we. The blocks indexed again can be of another chain.
+		return msg, h.clearChain()
+	case logs.LogLineMsg:
+		// Errors are dropped, logging them would be stored as a log line again
+		if m.Err == nil && m.Line != "" {
+			if m.HistorySeq, err = h.entries.Add(history.Logs, "", []byte(m.Line)); err == nil {
+				_ = h.pruneAfterAdd(history.Logs)
+			}
+		}
+		return m, nil
+	}
+	return msg, nil
+}
+
+// pruneAfterAdd prunes kind again after a tenth of ui.history.max_stored entries were added to it,
+// so a long session does not grow far past it. h.mu must be held.
+func (h *History) pruneAfterAdd(kind history.Kind) error {
+	h.added[kind]++
+	if h.added[kind] < max(h.maxStored/10, 1) {
+		return nil
+	}
+	h.added[kind] = 0
+	if err := h.entries.Prune(kind, h.maxStored); err != nil {
+		return fmt.Errorf("failed to prune history: %w", err)
+	}
+	return nil
+}
+
+// setChain records the block the first stored transaction is from, h.mu must be held
+func (h *History) setChain(height uint64, id string) error {
+	chain := &chainBlock{Height: height, ID: id}
+	value, err := json.Marshal(chain)
+	if err != nil {
+		return err
+	}
+	if _, err := h.entries.Add(history.Chain, "block", value); err != nil {
+		return err
+	}
+	h.chain = chain
+	return nil
+}
+
+// clearChain removes the stored transactions and events, h.mu must be held.
+// Log lines are kept, they are not tied to a chain.
+func (h *History) clearChain() error {
+	for _, kind := range []history.Kind{history.Transactions, history.Events, history.Chain} {
+		if err := h.entries.Clear(kind); err != nil {
+			return fmt.Errorf("failed to clear history: %w", err)
+		}
+	}
+	h.chain = nil
+	return nil
+}
+
+// checkChain removes the stored transactions and events if blockID, which returns the id of the block at
+// a height, no longer has the block they were indexed from. It reports whether they were removed.
+func (h *History) checkChain(blockID func(height uint64) (string, error)) (bool, error) {
+	h.mu.Lock()
+	chain := h.chain
+	h.mu.Unlock()
+	if chain == nil {
+		return false, nil
+	}
+
+	// Asked without holding h.mu, the emulator logs through the sink while answering
+	if id, err := blockID(chain.Height); err == nil && id == chain.ID {
+		return false, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || h.chain != chain {
+		return false, nil
+	}
+	return true, h.clearChain()
+}
+
+// addTransaction stores tx, a transaction that is indexed again replaces the stored one
+func (h *History) addTransaction(tx TransactionData) (uint64, error) {
+	if source, ok := h.sources[tx.ID]; ok {
+		tx.SourceFile = source.SourceFile
+		tx.IsInit = source.IsInit
+		delete(h.sources, tx.ID)
+	}
+
+	value, err := json.Marshal(newStoredTransaction(tx))
+	if err != nil {
+		return 0, err
+	}
+	return h.entries.Add(history.Transactions, tx.ID, value)
+}
+
+func (h *History) addEvent(event EventData) (uint64, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	return h.entries.Add(history.Events, fmt.Sprintf("%s-%d", event.TransactionID, event.EventIndex), value)
+}
+
+// setSource records which file sent a transaction, the message can arrive before or after the transaction is indexed
+func (h *History) setSource(msg TransactionSourceMsg) error {
+	value, ok, err := h.entries.Get(history.Transactions, msg.TransactionID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		h.sources[msg.TransactionID] = msg
+		return nil
+	}
+
+	var stored storedTransaction
+	if err := unmarshalWithNumbers(value, &stored); err != nil {
+		return err
+	}
+	stored.SourceFile = msg.SourceFile
+	stored.IsInit = msg.IsInit
+	if value, err = json.Marshal(stored); err != nil {
+		return err
+	}
+	_, err = h.entries.Update(history.Transactions, msg.TransactionID, value)
+	return err
+}
+
+// storedTransaction is a TransactionData as it is kept in the history. Decoded EVM transactions do not
+// serialize, so EVM events keep their raw cadence event to decode them again.
+type storedTransaction struct {
+	ID                string          `json:"id"`
+	BlockID           string          `json:"blockId"`
+	BlockHeight       uint64          `json:"blockHeight"`
+	Authorizers       []string        `json:"authorizers"`
+	Status            string          `json:"status"`
+	Proposer          string          `json:"proposer"`
+	Payer             string          `json:"payer"`
+	GasLimit          uint64          `json:"gasLimit"`
+	Script            string          `json:"script"`
+	HighlightedScript string          `json:"highlightedScript"` // Highlighting is too slow to repeat for every loaded transaction
+	Arguments         []ArgumentData  `json:"arguments"`
+	Events            []storedEvent   `json:"events"`
+	Type              TransactionType `json:"type"`
+	Error             string          `json:"error,omitempty"`
+	Timestamp         time.Time       `json:"timestamp"`
+	Index             int             `json:"index"`
+	SourceFile        string          `json:"sourceFile,omitempty"`
+	IsInit            bool            `json:"isInit,omitempty"`
+}
+
+type storedEvent struct {
+	ID            string                 `json:"id"`
+	TransactionID string                 `json:"transactionId"`
+	Name          string                 `json:"name"`
+	EventIndex    uint32                 `json:"eventIndex"`
+	Fields        map[string]interface{} `json:"fields"`
+	Addresses     map[string][]string    `json:"addresses,omitempty"`
+	Raw           json.RawMessage        `json:"raw,omitempty"` // JSON-CDC of EVM.TransactionExecuted events
+}
+
+func newStoredTransaction(tx TransactionData) storedTransaction {
+	stored := storedTransaction{
+		ID:                tx.ID,
+		BlockID:           tx.BlockID,
+		BlockHeight:       tx.BlockHeight,
+		Authorizers:       tx.Authorizers,
+		Status:            tx.Status,
+		Proposer:          tx.Proposer,
+		Payer:             tx.Payer,
+		GasLimit:          tx.GasLimit,
+		Script:            tx.Script,
+		HighlightedScript: tx.HighlightedScript,
+		Arguments:         tx.Arguments,
+		Type:              tx.Type,
+		Error:             tx.Error,
+		Timestamp:         tx.Timestamp,
+		Index:             tx.Index,
+		SourceFile:        tx.SourceFile,
+		IsInit:            tx.IsInit,
+	}
+	for _, event := range tx.Events {
+		e := storedEvent{
+			ID:            event.Id,
+			TransactionID: event.TransactionId,
+			Name:          event.Name,
+			EventIndex:    event.EventIndex,
+			Fields:        event.Fields,
+			Addresses:     event.Addresses,
+		}
+		if strings.Contains(event.Name, "EVM.TransactionExecuted") {
+			if raw, err := jsoncdc.Encode(event.RawEvent); err == nil {
+				e.Raw = raw
+			}
+		}
+		stored.Events = append(stored.Events, e)
+	}
+	return stored
+}
+
+func (s storedTransaction) transactionData() TransactionData {
+	tx := TransactionData{
+		ID:                s.ID,
+		BlockID:           s.BlockID,
+		BlockHeight:       s.BlockHeight,
+		Authorizers:       s.Authorizers,
+		Status:            s.Status,
+		Proposer:          s.Proposer,
+		Payer:             s.Payer,
+		GasLimit:          s.GasLimit,
+		Script:            s.Script,
+		HighlightedScript: s.HighlightedScript,
+		Type:              s.Type,
+		Error:             s.Error,
+		Timestamp:         s.Timestamp,
+		Index:             s.Index,
+		SourceFile:        s.SourceFile,
+		IsInit:            s.IsInit,
+	}
+	for _, arg := range s.Arguments {
+		tx.Arguments = append(tx.Arguments, ArgumentData{Name: arg.Name, Value: restoreNumbers(arg.Value)})
+	}
+	for _, e := range s.Events {
+		event := overflow.OverflowEvent{
+			Id:            e.ID,
+			TransactionId: e.TransactionID,
+			Name:          e.Name,
+			EventIndex:    e.EventIndex,
+			Fields:        restoreNumbers(e.Fields).(map[string]interface{}),
+			Addresses:     e.Addresses,
+		}
+		if len(e.Raw) > 0 {
+			if value, err := jsoncdc.Decode(nil, e.Raw); err == nil {
+				if raw, ok := value.(cadence.Event); ok {
+					event.RawEvent = raw
+				}
+			}
+		}
+		tx.Events = append(tx.Events, event)
+	}
+	tx.EVMTransactions, _ = decodeEVMTransactions(tx.Events)
+	return tx
+}
+
+// unmarshalWithNumbers decodes numbers as json.Number, so large integers keep their precision
+func unmarshalWithNumbers(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// restoreNumbers turns the json.Number values left by unmarshalWithNumbers into integers where they are whole,
+// like the values the indexer shows
+func restoreNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return map[string]interface{}{}
+		}
+		for key, value := range v {
+			v[key] = restoreNumbers(value)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = restoreNumbers(value)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n
+		}
+		if n, err := v.Float64(); err == nil {
+			return n
+		}
+		return v.String()
+	}
+	return v
+}
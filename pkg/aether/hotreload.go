@@ -0,0 +1,217 @@
+package aether
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bjartek/aether/pkg/flow"
+	"github.com/fsnotify/fsnotify"
+	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/project"
+)
+
+// reloadDebounce groups the file events of one save, editors often write a file in several steps
+const reloadDebounce = 300 * time.Millisecond
+
+// ContractReloadMsg is sent when a contract was redeployed after its source file changed
+type ContractReloadMsg struct {
+	Contract   string
+	Account    string
+	File       string
+	Success    bool
+	Redeployed bool   // The update was rejected, so the contract was removed and deployed again
+	Error      string // Checker or update error when Success is false
+}
+
+// HotReloadEnabled reports whether contracts are redeployed when their source changes
+func (a *Aether) HotReloadEnabled() bool {
+	return a.isLocal() && a.Emulator != nil && a.Config != nil && a.Config.Flow.HotReload.Enabled
+}
+
+// startContractWatcher watches the source files of the contracts in the emulator deployment and
+// redeploys a contract when its file is saved. It runs until aether stops.
+func (a *Aether) startContractWatcher() {
+	if !a.HotReloadEnabled() {
+		return
+	}
+
+	contracts, err := a.Overflow.State.DeploymentContractsByNetwork(a.Overflow.Network)
+	if err != nil {
+		a.Logger.Error().Err(err).Msg("Failed to read deployment, contract hot reload is disabled")
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		a.Logger.Error().Err(err).Msg("Failed to watch contracts, contract hot reload is disabled")
+		return
+	}
+
+	// Watch the folders, editors that save by renaming a temporary file would otherwise drop the watch
+	files := map[string]string{} // Absolute path to the location in flow.json
+	for _, c := range contracts {
+		abs, err := filepath.Abs(c.Location())
+		if err != nil {
+			continue
+		}
+		if _, ok := files[abs]; ok {
+			continue
+		}
+		if err := watcher.Add(filepath.Dir(abs)); err != nil {
+			a.Logger.Warn().Err(err).Str("file", c.Location()).Msg("Failed to watch contract")
+			continue
+		}
+		files[abs] = c.Location()
+	}
+
+	a.Logger.Info().Int("files", len(files)).Msg("Watching contracts, they are redeployed on save")
+	go a.watchContracts(watcher, files)
+}
+
+// watchContracts redeploys the contracts of changed files until aether stops
+func (a *Aether) watchContracts(watcher *fsnotify.Watcher, files map[string]string) {
+	defer func() { _ = watcher.Close() }()
+
+	changed := map[string]bool{}
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			abs, err := filepath.Abs(event.Name)
+			if err != nil {
+				continue
+			}
+			if location, ok := files[abs]; ok {
+				changed[location] = true
+				timer.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			a.Logger.Warn().Err(err).Msg("Contract watcher error")
+
+		case <-timer.C:
+			locations := make([]string, 0, len(changed))
+			for location := range changed {
+				locations = append(locations, location)
+			}
+			sort.Strings(locations)
+			changed = map[string]bool{}
+			a.reloadContracts(locations)
+		}
+	}
+}
+
+// reloadContracts redeploys every contract deployed from one of the locations and then runs
+// the configured init transactions again
+func (a *Aether) reloadContracts(locations []string) {
+	o := a.Overflow
+	// The deployment reads the contract code from disk every time
+	contracts, err := o.State.DeploymentContractsByNetwork(o.Network)
+	if err != nil {
+		a.Logger.Error().Err(err).Msg("Failed to read deployment for contract hot reload")
+		return
+	}
+
+	// Deploying waits for the transaction to be sealed, which never happens when blocks are committed by hand
+	if a.ManualBlocks() {
+		a.Emulator.EnableAutoMine()
+		defer a.Emulator.DisableAutoMine()
+	}
+
+	deployed := false
+	for _, location := range locations {
+		for _, c := range contracts {
+			if c.Location() != location {
+				continue
+			}
+			msg, ok := a.redeployContract(c)
+			if !ok {
+				continue
+			}
+			deployed = deployed || msg.Success
+			if a.sink != nil {
+				a.sink.Send(msg)
+			}
+		}
+	}
+
+	if deployed {
+		a.rerunInitTransactions()
+	}
+}
+
+// redeployContract updates a contract, or removes and deploys it again when the update is rejected and
+// flow.hot_reload.remove_incompatible is on. ok is false when the code did not change.
+func (a *Aether) redeployContract(c *project.Contract) (ContractReloadMsg, bool) {
+	o := a.Overflow
+	msg := ContractReloadMsg{Contract: c.Name, Account: c.AccountName, File: c.Location()}
+	l := a.Logger.With().Str("contract", c.Name).Str("account", c.AccountName).Logger()
+
+	account, err := o.State.Accounts().ByName(c.AccountName)
+	if err != nil {
+		msg.Error = err.Error()
+		return msg, true
+	}
+
+	script := flowkit.Script{Code: c.Code(), Args: c.Args, Location: c.Location()}
+	_, _, err = o.Flowkit.AddContract(a.ctx, account, script, flowkit.UpdateExistingContract(true))
+	if err != nil && strings.Contains(err.Error(), "is the same as the contract provided") {
+		// Saved without changes, flowkit does not export this error
+		return msg, false
+	}
+
+	// Only an incompatible update is worth a removal, a contract that does not check would just be gone
+	if err != nil && a.Config.Flow.HotReload.RemoveIncompatible && a.Config.Flow.Emulator.ContractRemovalEnabled &&
+		strings.Contains(err.Error(), "cannot update contract") {
+		l.Warn().Err(err).Msgf("Update of contract %s rejected, removing and deploying it again, everything stored with it is lost", c.Name)
+		if _, rmErr := o.Flowkit.RemoveContract(a.ctx, account, c.Name); rmErr != nil {
+			l.Error().Err(rmErr).Msg("Failed to remove contract")
+		} else {
+			msg.Redeployed = true
+			_, _, err = o.Flowkit.AddContract(a.ctx, account, script, flowkit.UpdateExistingContract(true))
+		}
+	}
+
+	if err != nil {
+		l.Error().Err(err).Msg("Failed to redeploy contract")
+		msg.Error = err.Error()
+		return msg, true
+	}
+
+	l.Info().Bool("redeployed", msg.Redeployed).Msg("Reloaded contract")
+	msg.Success = true
+	return msg, true
+}
+
+// rerunInitTransactions runs the init transactions configured in flow.hot_reload.init_transactions
+// again, e.g. to set up the resources a removed contract lost
+func (a *Aether) rerunInitTransactions() {
+	names := a.Config.Flow.HotReload.InitTransactions
+	if len(names) == 0 || a.initPath == "" {
+		return
+	}
+
+	callback := initProgressCallback(a.sink)
+	for _, name := range names {
+		path := filepath.Join(a.initPath, name)
+		if err := flow.RunInitTransaction(a.Overflow, a.runnerOverflow, path, a.Logger, callback); err != nil {
+			a.Logger.Error().Err(err).Str("file", name).Msg("Failed to run init transaction after contract reload")
+		}
+	}
+}
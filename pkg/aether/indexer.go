@@ -0,0 +1,351 @@
+package aether
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjartek/aether/pkg/chroma"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/flow"
+	"github.com/bjartek/overflow/v2"
+	"github.com/onflow/flow-evm-gateway/models"
+)
+
+// indexerRun is a running indexer, done is closed when its receiver goroutine has returned
+type indexerRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startIndexer streams blocks from startHeight and sends their transactions, events and height to the sink.
+// It runs until Stop is called or Reindex replaces it.
+func (a *Aether) startIndexer(startHeight uint64) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	run := &indexerRun{cancel: cancel, done: make(chan struct{})}
+
+	a.mu.Lock()
+	a.indexer = run
+	a.mu.Unlock()
+
+	// The previous receiver has returned, so the counters start over with the new run
+	a.filter = flow.NewTransactionFilter(a.Config.Indexer.Filter)
+	a.filterSeen, a.filterKept = 0, 0
+
+	o := a.Overflow
+	pollInterval := a.Config.Indexer.PollingInterval
+	concurrency := a.Config.Indexer.Concurrency
+	overflowChannel := make(chan flow.BlockResult)
+
+	go func() {
+		a.Logger.Info().Uint64("startHeight", startHeight).Msg("Started streaming")
+
+		var err error
+		if a.Config.Indexer.Backend == config.IndexerBackendSubscription {
+			host := a.Config.Indexer.SubscriptionHost
+			if host == "" {
+				host = o.Network.Host
+			}
+			err = flow.SubscribeTransactions(ctx, o, host, pollInterval, concurrency, startHeight, a.Logger, overflowChannel)
+		} else {
+			err = flow.StreamTransactions(ctx, o, pollInterval, concurrency, startHeight, a.Logger, overflowChannel)
+		}
+		if err != nil {
+			if errors.Is(err, flow.ErrChainReset) {
+				// E.g. the emulator was restarted outside of aether, start over instead of waiting for blocks that are gone
+				a.Logger.Warn().Msg("Chain was reset, indexing again from the start")
+				go a.reindex(run)
+			} else if strings.Contains(err.Error(), "context canceled") {
+				a.Logger.Info().Msg("Streaming stopped due to context cancellation")
+			} else {
+				a.Logger.Warn().Err(err).Msg("Streaming encountered an error")
+			}
+		}
+	}()
+
+	go func() {
+		defer close(run.done)
+		for {
+			select {
+			case <-ctx.Done():
+				a.Logger.Info().Msg("Block receiver goroutine stopping due to context cancellation")
+				return
+			case br, ok := <-overflowChannel:
+				if !ok {
+					a.Logger.Info().Msg("Channel has been closed. Crawler stopped completely.")
+					return
+				}
+				a.processBlock(br)
+			}
+		}
+	}()
+}
+
+// stopIndexer stops the running indexer, the blocks it indexed are kept
+func (a *Aether) stopIndexer() {
+	a.mu.Lock()
+	run := a.indexer
+	a.mu.Unlock()
+	if run != nil {
+		run.cancel()
+	}
+}
+
+// Reindex stops the running indexer, tells the views to clear what they have and indexes again from the start height.
+// It is used after the emulator state has been replaced, e.g. when reverting to a snapshot.
+func (a *Aether) Reindex() {
+	a.reindex(nil)
+}
+
+// reindex replaces the running indexer, if only is set only when that indexer is still the running one
+func (a *Aether) reindex(only *indexerRun) {
+	a.reindexMu.Lock()
+	defer a.reindexMu.Unlock()
+
+	a.mu.Lock()
+	run := a.indexer
+	a.mu.Unlock()
+
+	if run == nil || (only != nil && run != only) {
+		return
+	}
+
+	// Wait for the old receiver so no stale blocks arrive after the reset
+	run.cancel()
+	<-run.done
+
+	a.Logger.Info().Uint64("startHeight", a.startHeight).Msg("Re-indexing")
+	if a.sink != nil {
+		a.sink.Send(IndexerResetMsg{})
+	}
+	a.startIndexer(a.startHeight)
+}
+
+// processBlock sends the transactions and events in a block to the sink
+func (a *Aether) processBlock(br flow.BlockResult) {
+	l := br.Logger
+	sink := a.sink
+
+	// Blocks after indexer.end_height can already be on their way when the indexer stops
+	if a.endHeight != 0 && br.Block.Height > a.endHeight {
+		a.stopIndexer()
+		return
+	}
+
+	if br.Error != nil {
+		l.Warn().Err(br.Error).Int("failures", br.Failures).Dur("retryIn", br.RetryIn).Msg("Failed fetching block")
+		a.degraded = true
+		if sink != nil {
+			sink.Send(IndexerStatusMsg{
+				Degraded: true,
+				Height:   br.Block.Height,
+				Failures: br.Failures,
+				RetryIn:  br.RetryIn,
+				Error:    br.Error.Error(),
+			})
+		}
+		return
+	}
+	if a.degraded {
+		a.degraded = false
+		l.Info().Msg("Indexer recovered")
+		if sink != nil {
+			sink.Send(IndexerStatusMsg{Degraded: false, Height: br.Block.Height})
+		}
+	}
+
+	// Name COAs created in this block before their transactions are shown
+	if createsCOA(br) {
+		a.registerCOAs(a.Overflow)
+	}
+
+	// Drop what the watchlist in indexer.filter does not cover before anything is shown
+	transactions := br.Transactions
+	if a.filter != nil {
+		transactions = a.filter.Filter(br.Transactions)
+		a.filterSeen += uint64(len(br.Transactions))
+		a.filterKept += uint64(len(transactions))
+		if sink != nil && len(br.Transactions) > 0 {
+			sink.Send(IndexerFilterMsg{Seen: a.filterSeen, Kept: a.filterKept})
+		}
+	}
+
+	// Send transactions directly to UI if there are any
+	if len(transactions) > 0 && sink != nil {
+		for _, ot := range transactions {
+			txData := newTransactionData(ot, br)
+			sink.Send(BlockTransactionMsg{
+				TransactionData: txData,
+			})
+
+			// Send individual event messages
+			for eventIndex, event := range txData.Events {
+				eventData := EventData{
+					Name:             event.Name,
+					BlockHeight:      br.Block.Height,
+					BlockID:          br.Block.ID.String(),
+					TransactionID:    ot.Id,
+					TransactionIndex: ot.TransactionIndex,
+					EventIndex:       eventIndex,
+					Fields:           event.Fields,
+					Timestamp:        time.Now(),
+				}
+				sink.Send(BlockEventMsg{
+					EventData: eventData,
+				})
+			}
+		}
+	}
+
+	// Send block height update to dashboard
+	if sink != nil {
+		sink.Send(BlockHeightMsg{
+			Height:    br.Block.Height,
+			Timestamp: br.Block.Timestamp,
+		})
+	}
+
+	a.saveCheckpoint(br.Block.Height, false)
+
+	if a.endHeight != 0 && br.Block.Height == a.endHeight {
+		l.Info().Msg("Reached indexer.end_height, stopped indexing")
+		a.stopIndexer()
+	}
+
+	// Log the block processing
+	txCount := len(transactions)
+
+	if txCount > 0 {
+		l.Info().
+			Uint64("height", br.Block.Height).
+			Int("txCount", txCount).
+			Msg("Processed block")
+	}
+}
+
+// createsCOA reports whether a transaction in the block created a Cadence-Owned Account
+func createsCOA(br flow.BlockResult) bool {
+	for _, ot := range br.Transactions {
+		for _, event := range ot.Events {
+			if strings.HasSuffix(event.Name, "EVM.CadenceOwnedAccountCreated") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newTransactionData converts an indexed transaction to the data shown in the UI
+func newTransactionData(ot overflow.OverflowTransaction, br flow.BlockResult) TransactionData {
+	// Extract all authorizers
+	authorizers := ot.Authorizers
+	if len(authorizers) == 0 {
+		authorizers = []string{"N/A"}
+	}
+
+	// Extract proposer and payer
+	proposer := "N/A"
+	if ot.ProposalKey.Address.String() != "" {
+		proposer = fmt.Sprintf("0x%s", ot.ProposalKey.Address.Hex())
+	}
+
+	payer := "N/A"
+	if ot.Payer != "" {
+		payer = ot.Payer
+	}
+	// Determine status
+	status := ot.Status
+	if ot.Error != nil {
+		status = "Failed"
+	}
+
+	// Store full script - user can scroll if needed
+	script := string(ot.Script)
+	highlightedScript := chroma.HighlightCadence(script)
+
+	// Format arguments as structured data
+	args := make([]ArgumentData, 0, len(ot.Arguments))
+	for i, arg := range ot.Arguments {
+		// Use the key field as the argument name, fallback to index if not available
+		name := arg.Key
+		if name == "" {
+			name = fmt.Sprintf("argument%d", i)
+		}
+		argData := ArgumentData{
+			Name:  name,
+			Value: arg.Value, // Keep as interface{} for proper formatting
+		}
+		args = append(args, argData)
+	}
+
+	// Create error message
+	errMsg := ""
+	if ot.Error != nil {
+		errMsg = ot.Error.Error()
+	}
+
+	// Store events directly
+	events := ot.Events
+
+	// Detect and decode EVM transactions from events
+	evmTransactions, txType := decodeEVMTransactions(events)
+
+	return TransactionData{
+		ID:                ot.Id,
+		BlockID:           br.Block.ID.String(),
+		BlockHeight:       br.Block.Height,
+		Authorizers:       authorizers,
+		Status:            status,
+		Proposer:          proposer,
+		Payer:             payer,
+		GasLimit:          ot.GasLimit,
+		Script:            script,
+		HighlightedScript: highlightedScript,
+		Arguments:         args,
+		Events:            events,
+		EVMTransactions:   evmTransactions,
+		Type:              txType,
+		Error:             errMsg,
+		Timestamp:         time.Now(),
+		Index:             ot.TransactionIndex,
+	}
+}
+
+// decodeEVMTransactions decodes the EVM transactions in the EVM.TransactionExecuted events of a transaction
+// and tells if the transaction is a Flow, EVM or mixed transaction
+func decodeEVMTransactions(events []overflow.OverflowEvent) ([]EVMTransactionData, TransactionType) {
+	evmTransactions := make([]EVMTransactionData, 0)
+	hasEVMEvents := false
+	hasNonEVMEvents := false
+
+	for _, event := range events {
+		// Check if this is an EVM.TransactionExecuted event
+		if strings.Contains(event.Name, "EVM.TransactionExecuted") {
+			hasEVMEvents = true
+			tx, receipt, payload, err := models.DecodeTransactionEvent(event.RawEvent)
+			if err != nil {
+				// Skip events that fail to decode
+				continue
+			}
+			evmTx := EVMTransactionData{
+				Transaction: tx,
+				Receipt:     receipt,
+				Payload:     payload,
+			}
+			evmTransactions = append(evmTransactions, evmTx)
+		} else {
+			hasNonEVMEvents = true
+		}
+	}
+
+	// Determine transaction type
+	txType := TransactionTypeFlow // Default to flow
+	if hasEVMEvents && !hasNonEVMEvents {
+		txType = TransactionTypeEVM
+	} else if hasEVMEvents && hasNonEVMEvents {
+		txType = TransactionTypeMixed
+	}
+	return evmTransactions, txType
+}
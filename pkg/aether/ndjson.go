@@ -0,0 +1,177 @@
+package aether
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bjartek/aether/pkg/logs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TransactionRecord is the JSON representation of a TransactionData.
+// Events and EVM transactions are flattened since the raw cadence and EVM types do not serialize cleanly.
+type TransactionRecord struct {
+	Type            string                 `json:"type"`
+	ID              string                 `json:"id"`
+	BlockID         string                 `json:"blockId"`
+	BlockHeight     uint64                 `json:"blockHeight"`
+	Index           int                    `json:"index"`
+	Status          string                 `json:"status"`
+	TransactionType TransactionType        `json:"transactionType"`
+	Authorizers     []string               `json:"authorizers"`
+	Proposer        string                 `json:"proposer"`
+	Payer           string                 `json:"payer"`
+	GasLimit        uint64                 `json:"gasLimit"`
+	Script          string                 `json:"script"`
+	Arguments       map[string]interface{} `json:"arguments,omitempty"`
+	Events          []string               `json:"events,omitempty"`
+	EVMTransactions []EVMTransactionRecord `json:"evmTransactions,omitempty"`
+	Error           string                 `json:"error,omitempty"`
+	SourceFile      string                 `json:"sourceFile,omitempty"`
+	IsInit          bool                   `json:"isInit,omitempty"`
+	Timestamp       time.Time              `json:"timestamp"`
+}
+
+// EVMTransactionRecord is the JSON representation of a decoded EVM transaction
+type EVMTransactionRecord struct {
+	Hash         string `json:"hash"`
+	From         string `json:"from,omitempty"`
+	To           string `json:"to,omitempty"`
+	Value        string `json:"value,omitempty"`
+	GasUsed      uint64 `json:"gasUsed"`
+	ErrorMessage string `json:"error,omitempty"`
+}
+
+// EventRecord is the JSON representation of an EventData
+type EventRecord struct {
+	Type             string                 `json:"type"`
+	Name             string                 `json:"name"`
+	BlockHeight      uint64                 `json:"blockHeight"`
+	BlockID          string                 `json:"blockId"`
+	TransactionID    string                 `json:"transactionId"`
+	TransactionIndex int                    `json:"transactionIndex"`
+	EventIndex       int                    `json:"eventIndex"`
+	Fields           map[string]interface{} `json:"fields"`
+	Timestamp        time.Time              `json:"timestamp"`
+}
+
+// NewTransactionRecord converts a TransactionData into its JSON representation
+func NewTransactionRecord(tx TransactionData) TransactionRecord {
+	record := TransactionRecord{
+		Type:            "transaction",
+		ID:              tx.ID,
+		BlockID:         tx.BlockID,
+		BlockHeight:     tx.BlockHeight,
+		Index:           tx.Index,
+		Status:          tx.Status,
+		TransactionType: tx.Type,
+		Authorizers:     tx.Authorizers,
+		Proposer:        tx.Proposer,
+		Payer:           tx.Payer,
+		GasLimit:        tx.GasLimit,
+		Script:          tx.Script,
+		Error:           tx.Error,
+		SourceFile:      tx.SourceFile,
+		IsInit:          tx.IsInit,
+		Timestamp:       tx.Timestamp,
+	}
+
+	if len(tx.Arguments) > 0 {
+		record.Arguments = make(map[string]interface{}, len(tx.Arguments))
+		for _, arg := range tx.Arguments {
+			record.Arguments[arg.Name] = arg.Value
+		}
+	}
+
+	for _, event := range tx.Events {
+		record.Events = append(record.Events, event.Name)
+	}
+
+	for _, evmTx := range tx.EVMTransactions {
+		r := EVMTransactionRecord{
+			Hash: evmTx.Transaction.Hash().Hex(),
+		}
+		if from, err := evmTx.Transaction.From(); err == nil {
+			r.From = from.Hex()
+		}
+		if to := evmTx.Transaction.To(); to != nil {
+			r.To = to.Hex()
+		}
+		if value := evmTx.Transaction.Value(); value != nil {
+			r.Value = value.String()
+		}
+		if evmTx.Receipt != nil {
+			r.GasUsed = evmTx.Receipt.GasUsed
+		}
+		if evmTx.Payload != nil && evmTx.Payload.ErrorCode != 0 {
+			r.ErrorMessage = evmTx.Payload.ErrorMessage
+		}
+		record.EVMTransactions = append(record.EVMTransactions, r)
+	}
+
+	return record
+}
+
+// NewEventRecord converts an EventData into its JSON representation
+func NewEventRecord(event EventData) EventRecord {
+	return EventRecord{
+		Type:             "event",
+		Name:             event.Name,
+		BlockHeight:      event.BlockHeight,
+		BlockID:          event.BlockID,
+		TransactionID:    event.TransactionID,
+		TransactionIndex: event.TransactionIndex,
+		EventIndex:       event.EventIndex,
+		Fields:           event.Fields,
+		Timestamp:        event.Timestamp,
+	}
+}
+
+// NDJSONSink writes every transaction and event as one JSON object per line.
+// Log lines are passed through to a separate writer so they never corrupt the JSON stream.
+type NDJSONSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	logOut  io.Writer
+	onError func(error)
+}
+
+// NewNDJSONSink creates a sink writing records to out and log lines to logOut (may be nil to drop logs)
+func NewNDJSONSink(out io.Writer, logOut io.Writer) *NDJSONSink {
+	return &NDJSONSink{
+		encoder: json.NewEncoder(out),
+		logOut:  logOut,
+	}
+}
+
+// OnError registers a callback for records that could not be written
+func (s *NDJSONSink) OnError(fn func(error)) {
+	s.onError = fn
+}
+
+// Send implements events.Sink
+func (s *NDJSONSink) Send(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case BlockTransactionMsg:
+		s.write(NewTransactionRecord(msg.TransactionData))
+	case BlockEventMsg:
+		s.write(NewEventRecord(msg.EventData))
+	case logs.LogLineMsg:
+		if s.logOut != nil {
+			s.mu.Lock()
+			_, _ = io.WriteString(s.logOut, msg.Line)
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *NDJSONSink) write(record interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.encoder.Encode(record); err != nil && s.onError != nil {
+		s.onError(fmt.Errorf("failed to write record: %w", err))
+	}
+}
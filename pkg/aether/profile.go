@@ -0,0 +1,104 @@
+package aether
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// Procedure kinds in the computation report
+const (
+	ProcedureTransaction = "transaction"
+	ProcedureScript      = "script"
+)
+
+// ProcedureProfile is the computation used by one transaction or script executed on the emulator
+type ProcedureProfile struct {
+	ID          string // Transaction ID or script ID
+	Kind        string // ProcedureTransaction or ProcedureScript
+	Path        string // Source file, if the emulator knows it
+	Computation uint64
+	Memory      uint64 // Estimated memory usage
+	Intensities []ComputationIntensity
+	Code        string
+	Arguments   []string
+}
+
+// ComputationIntensity is how often one kind of operation was metered, e.g. statements or function invocations
+type ComputationIntensity struct {
+	Kind  string
+	Count uint64
+}
+
+// ProfilingEnabled reports whether computation is reported, which is the case when aether runs the local emulator
+func (a *Aether) ProfilingEnabled() bool {
+	return a.Emulator != nil
+}
+
+// Profiles returns the computation used by every transaction and script executed on the emulator,
+// most expensive first. It waits for the emulator to finish what it executes, so do not call it from Update:
+// the emulator logs to the TUI while it executes.
+func (a *Aether) Profiles() ([]ProcedureProfile, error) {
+	if !a.ProfilingEnabled() {
+		return nil, nil
+	}
+	report := a.Emulator.ComputationReport()
+	if report == nil {
+		return nil, nil
+	}
+
+	// Executions add to the report, so it is copied while none run
+	var transactions, scripts map[string]emulator.ProcedureReport
+	err := a.withEmulatorPaused(func() {
+		transactions = maps.Clone(report.Transactions)
+		scripts = maps.Clone(report.Scripts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read computation report: %w", err)
+	}
+
+	profiles := make([]ProcedureProfile, 0, len(transactions)+len(scripts))
+	for id, r := range transactions {
+		profiles = append(profiles, newProcedureProfile(id, ProcedureTransaction, r))
+	}
+	for id, r := range scripts {
+		profiles = append(profiles, newProcedureProfile(id, ProcedureScript, r))
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i].Computation != profiles[j].Computation {
+			return profiles[i].Computation > profiles[j].Computation
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+	return profiles, nil
+}
+
+func newProcedureProfile(id, kind string, r emulator.ProcedureReport) ProcedureProfile {
+	intensities := make([]ComputationIntensity, 0, len(r.Intensities))
+	for k, count := range r.Intensities {
+		if count == 0 {
+			continue
+		}
+		intensities = append(intensities, ComputationIntensity{Kind: k, Count: count})
+	}
+	sort.Slice(intensities, func(i, j int) bool {
+		if intensities[i].Count != intensities[j].Count {
+			return intensities[i].Count > intensities[j].Count
+		}
+		return intensities[i].Kind < intensities[j].Kind
+	})
+
+	return ProcedureProfile{
+		ID:          id,
+		Kind:        kind,
+		Path:        r.Path,
+		Computation: r.ComputationUsed,
+		Memory:      r.MemoryEstimate,
+		Intensities: intensities,
+		Code:        r.Code,
+		Arguments:   r.Arguments,
+	}
+}
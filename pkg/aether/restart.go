@@ -0,0 +1,109 @@
+package aether
+
+import (
+	"fmt"
+
+	"github.com/bjartek/aether/pkg/flow"
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// Services that can be restarted from the dashboard
+const (
+	ServiceEmulator  = "emulator"
+	ServiceGateway   = "evm-gateway"
+	ServiceDevWallet = "dev-wallet"
+	ServiceFrontend  = "frontend"
+)
+
+// ServiceController restarts the local services aether runs next to the UI
+type ServiceController interface {
+	// RestartEmulator starts a new emulator and waits until it is ready. The dev wallet and
+	// the EVM gateway talk to the emulator and are restarted with it.
+	RestartEmulator() (emulator.Emulator, error)
+	RestartGateway() error
+	RestartDevWallet() error
+	RestartFrontend() error
+}
+
+// RestartableServices returns the services that can be restarted, in the order they are shown
+func (a *Aether) RestartableServices() []string {
+	if a.Services == nil || !a.isLocal() {
+		return nil
+	}
+	// After a failed emulator restart nothing runs, the emulator has to be restarted first
+	if a.Emulator == nil {
+		return []string{ServiceEmulator}
+	}
+	services := []string{ServiceEmulator, ServiceDevWallet}
+	if a.Config.Flow.Emulator.SetupEVMEnabled {
+		services = append(services, ServiceGateway)
+	}
+	if a.Config.FrontendCommand != "" {
+		services = append(services, ServiceFrontend)
+	}
+	return services
+}
+
+// RestartService restarts one service. After an emulator restart the indexer starts over and, when
+// redeploy is set, the contracts are deployed and the init transactions run again.
+func (a *Aether) RestartService(name string, redeploy bool) error {
+	if a.Services == nil {
+		return fmt.Errorf("services can only be restarted on the local emulator")
+	}
+	if !a.restarting.CompareAndSwap(false, true) {
+		return fmt.Errorf("a restart is already running")
+	}
+	defer a.restarting.Store(false)
+
+	a.Logger.Info().Str("service", name).Bool("redeploy", redeploy).Msg("Restarting service")
+	switch name {
+	case ServiceEmulator:
+		return a.restartEmulator(redeploy)
+	case ServiceGateway:
+		return a.Services.RestartGateway()
+	case ServiceDevWallet:
+		return a.Services.RestartDevWallet()
+	case ServiceFrontend:
+		return a.Services.RestartFrontend()
+	}
+	return fmt.Errorf("unknown service %q", name)
+}
+
+func (a *Aether) restartEmulator(redeploy bool) error {
+	emu, err := a.Services.RestartEmulator()
+	if err != nil {
+		// The old emulator is stopped, coverage, profiles, snapshots and time travel are off until a restart works
+		a.Emulator = nil
+		a.clock = nil
+		return fmt.Errorf("emulator restart failed, the local services are stopped: %w", err)
+	}
+	a.Emulator = emu
+	a.startClock()
+
+	// The blocks the indexer was waiting for are gone, index the new chain from the start
+	a.Reindex()
+
+	if redeploy {
+		o := a.Overflow
+		if _, err := o.CreateAccountsE(a.ctx); err != nil {
+			return err
+		}
+		if err := a.deployContracts(a.ctx, o); err != nil {
+			return err
+		}
+		if err := a.fundEVMAccounts(o); err != nil {
+			return err
+		}
+		if err := a.deployEVMContracts(a.ctx); err != nil {
+			return err
+		}
+		if err := flow.RunInitTransactions(o, a.runnerOverflow, a.initPath, a.Logger, initProgressCallback(a.sink)); err != nil {
+			return err
+		}
+		a.registerCOAs(o)
+	}
+
+	a.enterManualBlocks()
+	a.Logger.Info().Msg("Emulator restarted")
+	return nil
+}
@@ -5,18 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/bjartek/aether/pkg/chroma"
 	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/events"
 	"github.com/bjartek/aether/pkg/flow"
 	"github.com/bjartek/overflow/v2"
 	"github.com/bjartek/underflow"
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/enescakir/emoji"
+	"github.com/onflow/flow-emulator/emulator"
 	"github.com/onflow/flow-evm-gateway/models"
-	"github.com/onflow/flow-go/fvm/evm/events"
+	evmEvents "github.com/onflow/flow-go/fvm/evm/events"
 	"github.com/rs/zerolog"
 )
 
@@ -25,28 +26,67 @@ type Aether struct {
 	FclCdc          []byte
 	Overflow        *overflow.OverflowState
 	AccountRegistry *AccountRegistry
-	Network         string // "testnet", "mainnet", or "emulator"
+	Network         string // "testnet", "mainnet", "emulator" or "emulator-fork:<network>@<height>"
 	Config          *config.Config
+	Emulator        emulator.Emulator // Local emulator, nil when following a network
+	Services        ServiceController // Restarts the local services, nil when following a network
+	History         *History          // Sent to the views before indexing starts, nil when not persisted
 	
 	// State for deferred init transaction execution (interactive mode)
 	pendingInitTx *pendingInitContext
+
+	// ctx and cancel stop everything started by Start
+	ctx    context.Context
+	cancel context.CancelFunc
+	sink   events.Sink
+
+	// indexer is the currently running indexer, replaced by Reindex
+	mu          sync.Mutex
+	reindexMu   sync.Mutex // Serializes Reindex
+	indexer     *indexerRun
+	startHeight uint64
+	endHeight   uint64 // Last block to index from indexer.end_height, 0 keeps following
+	degraded    bool   // True while the indexer retries a block it could not fetch
+
+	// Watchlist from indexer.filter, nil keeps every transaction. Only used by the block receiver.
+	filter     *flow.TransactionFilter
+	filterSeen uint64
+	filterKept uint64
+
+	// Checkpoint of the last processed block when following a network, see checkpoint.go
+	checkpointFile   string
+	checkpointHeight uint64
+	checkpointSaved  time.Time
+
+	// clock moves the emulator block timestamps ahead of the wall clock, nil until Start on the local emulator
+	clock *travelClock
+
+	// Used to run init transactions again after a contract hot reload
+	runnerOverflow *overflow.OverflowState
+	initPath       string
+
+	restarting atomic.Bool // Only one service is restarted at a time
+
+	evmAccounts []flow.EVMAccount // Named EOAs from evm.accounts, funded after the contracts are deployed
 }
 
 type pendingInitContext struct {
-	teaProgram *tea.Program
-	o          *overflow.OverflowState
-	oR         *overflow.OverflowState
-	basePath   string
+	sink     events.Sink
+	o        *overflow.OverflowState
+	oR       *overflow.OverflowState
+	basePath string
 }
 
 // BlockTransactionMsg is sent when a transaction is processed
 type BlockTransactionMsg struct {
 	TransactionData TransactionData
+	HistorySeq      uint64 // Place in the history, 0 when it is not stored
 }
 
 // BlockEventMsg is sent when an event is processed
 type BlockEventMsg struct {
-	EventData EventData
+	EventData  EventData
+	HistorySeq uint64 // Place in the history, 0 when it is not stored
 }
 
 // EventData holds event information for display
@@ -70,7 +110,7 @@ type ArgumentData struct {
 type EVMTransactionData struct {
 	Transaction models.Transaction
 	Receipt     *models.Receipt
-	Payload     *events.TransactionEventPayload
+	Payload     *evmEvents.TransactionEventPayload
 }
 
 // TransactionType represents the type of transaction
@@ -114,6 +154,7 @@ type TransactionMsg struct {
 type OverflowReadyMsg struct {
 	Overflow        *overflow.OverflowState
 	AccountRegistry *AccountRegistry
+	Restored        bool // True if the emulator state was restored from disk and setup is skipped
 }
 
 // InitTransactionMsg is sent when an init transaction executes
@@ -133,9 +174,38 @@ type TransactionSourceMsg struct {
 
 // BlockHeightMsg is sent periodically with the latest block height
 type BlockHeightMsg struct {
-	Height uint64
+	Height    uint64
+	Timestamp time.Time // Block timestamp, ahead of the wall clock after time travel on the emulator
 }
 
+// IndexRangeMsg is sent when the indexer backfills blocks from indexer.start_height, views show the
+// progress from the BlockHeightMsg that follow
+type IndexRangeMsg struct {
+	From   uint64 // First block of the range
+	To     uint64 // indexer.end_height, or the latest block at start when the indexer keeps following
+	Follow bool   // True if the indexer keeps following the network after To
+}
+
+// IndexerStatusMsg is sent when the indexer can not fetch the next block and retries it with backoff,
+// and again with Degraded false once it indexes blocks again
+type IndexerStatusMsg struct {
+	Degraded bool
+	Height   uint64        // Block the indexer is retrying
+	Failures int           // Consecutive failures
+	RetryIn  time.Duration // Wait before the next attempt
+	Error    string
+}
+
+// IndexerFilterMsg is sent after each block with transactions when indexer.filter is set
+type IndexerFilterMsg struct {
+	Seen uint64 // Transactions indexed since the indexer started
+	Kept uint64 // Of those, the ones that matched the filter and were shown
+}
+
+// IndexerResetMsg is sent before the indexer starts over from the first block,
+// e.g. after reverting to a snapshot. Views should drop everything they have indexed.
+type IndexerResetMsg struct{}
+
 // InitFolderSelectionMsg prompts the user to select an init transactions folder
 type InitFolderSelectionMsg struct {
 	Folders     []string // Available folders to choose from
@@ -147,58 +217,54 @@ type InitFolderSelectedMsg struct {
 	SelectedFolder string // The folder name selected by user (empty string = root)
 }
 
-func (a *Aether) Start(teaProgram *tea.Program) error {
-	ctx := context.Background()
-
-	// Define the two possible paths
-	path1 := "aether"
-	path2 := filepath.Join("cadence", "aether")
+// Start creates the overflow clients, starts the indexer and (in emulator mode) runs the
+// local setup. Everything that happens is reported as messages to the given sink.
+func (a *Aether) Start(sink events.Sink) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.ctx = ctx
+	a.cancel = cancel
+	a.sink = sink
 
-	// Check which path exists
-	var validPath string
-	basePath := ""
-	if _, err := os.Stat(path1); err == nil {
-		validPath = path1
-	} else if _, err := os.Stat(path2); err == nil {
-		validPath = path2
-		basePath = "cadence"
-	} else {
-		return fmt.Errorf("neither %q nor %q exists", path1, path2)
+	if a.isLocal() && a.Emulator != nil {
+		a.startControlServer()
 	}
 
-	// Configure underflow options for human-friendly output
-	underflowOptions := underflow.Options{
-		ByteArrayAsHex:             true,
-		ShowUnixTimestampsAsString: true,
-		TimestampFormat:            "2006-01-02 15:04:05 UTC",
+	validPath, basePath, err := DetectBasePath()
+	if err != nil {
+		return err
 	}
 
 	// Initialize overflow based on network mode
-	var o *overflow.OverflowState
-	if a.Network == "emulator" {
-		// Local emulator mode
-		o = overflow.Overflow(
-			overflow.WithExistingEmulator(),
-			overflow.WithLogNone(),
-			overflow.WithReturnErrors(),
-			overflow.WithTransactionFolderName("aether"),
-			overflow.WithBasePath(basePath),
-			overflow.WithUnderflowOptions(underflowOptions),
-			overflow.WithFlowForNewUsers(a.Config.Flow.NewUserBalance))
+	opts := append(networkOptions(a.Config),
+		overflow.WithLogNone(),
+		overflow.WithReturnErrors(),
+		overflow.WithTransactionFolderName("aether"),
+		overflow.WithBasePath(basePath),
+		overflow.WithUnderflowOptions(underflowOptions))
+	if a.isLocal() {
+		// Local emulator mode, possibly forked from a live network
+		opts = append(opts, overflow.WithFlowForNewUsers(a.Config.Flow.NewUserBalance))
 	} else {
 		// Network mode (testnet or mainnet)
 		a.Logger.Info().Str("network", a.Network).Msg("Initializing overflow for network")
-		o = overflow.Overflow(
-			overflow.WithNetwork(a.Network),
-			overflow.WithLogNone(),
-			overflow.WithReturnErrors(),
-			overflow.WithTransactionFolderName("aether"),
-			overflow.WithBasePath(basePath),
-			overflow.WithUnderflowOptions(underflowOptions))
+	}
+	o := overflow.Overflow(opts...)
+
+	// With persisted state the accounts, contracts and init transactions from the last run are still there
+	restored := false
+	if a.isLocal() && a.Config.Flow.Persist {
+		if err := a.resumeWorkingSnapshot(); err != nil {
+			return err
+		}
+		restored = hasPersistedState(ctx, o)
+	}
+
+	if a.isLocal() && a.Emulator != nil {
+		a.startClock()
 	}
 
 	// Only create accounts in local mode
-	if a.Network == "emulator" {
+	if a.isLocal() {
 		a.Logger.Info().Str("network", o.Network.Name).Msg("emulator")
 		_, err := o.CreateAccountsE(ctx)
 		if err != nil {
@@ -209,6 +275,12 @@ func (a *Aether) Start(teaProgram *tea.Program) error {
 
 	// Initialize account registry after accounts are created
 	a.AccountRegistry = NewAccountRegistry(o)
+	if a.isLocal() {
+		if err := a.registerEVMAccounts(); err != nil {
+			return err
+		}
+	}
+	a.registerCOAs(o)
 	dump := a.AccountRegistry.DebugDump()
 	a.Logger.Info().
 		Int("accounts", len(a.AccountRegistry.addressToName)).
@@ -216,42 +288,30 @@ func (a *Aether) Start(teaProgram *tea.Program) error {
 		Msg("Initialized account registry")
 
 	// Create second overflow instance for runner view with same underflow options
-	var oR *overflow.OverflowState
-	if a.Network == "emulator" {
-		oR = overflow.Overflow(
-			overflow.WithExistingEmulator(),
-			overflow.WithLogNone(),
-			overflow.WithReturnErrors(),
-			overflow.WithBasePath(basePath),
-			overflow.WithUnderflowOptions(underflowOptions))
-	} else {
-		oR = overflow.Overflow(
-			overflow.WithNetwork(a.Network),
-			overflow.WithLogNone(),
-			overflow.WithReturnErrors(),
-			overflow.WithBasePath(basePath),
-			overflow.WithUnderflowOptions(underflowOptions))
+	oR := NewRunnerOverflow(a.Config, basePath)
+	a.runnerOverflow = oR
+	a.initPath = validPath
+	if a.Config.Flow.InitTransactionsFolder != "" {
+		a.initPath = filepath.Join(validPath, a.Config.Flow.InitTransactionsFolder)
 	}
 
 	// Send overflow ready message to UI
-	if teaProgram != nil {
-		teaProgram.Send(OverflowReadyMsg{
+	if sink != nil {
+		sink.Send(OverflowReadyMsg{
 			Overflow:        oR,
 			AccountRegistry: a.AccountRegistry,
+			Restored:        restored,
 		})
 	}
 
-	overflowChannel := make(chan flow.BlockResult)
-
-	// Determine starting block height and polling interval based on network mode
+	// Determine starting block height based on network mode
 	var startHeight uint64
-	pollInterval := a.Config.Indexer.PollingInterval
 
 	if a.Network == "emulator" {
 		// Local emulator mode - start from block 1
 		startHeight = 1
 	} else {
-		// Network mode - start from latest block
+		// Network or fork mode - start from latest block, a fork would otherwise fetch the whole chain
 		latestBlock, err := o.GetLatestBlock(ctx)
 		if err != nil {
 			a.Logger.Error().Err(err).Str("network", a.Network).Msg("Failed to get latest block")
@@ -259,214 +319,72 @@ func (a *Aether) Start(teaProgram *tea.Program) error {
 		}
 		startHeight = latestBlock.Height
 
-		a.Logger.Info().
-			Str("network", a.Network).
-			Uint64("startHeight", startHeight).
-			Dur("pollInterval", pollInterval).
-			Msg("Starting to stream from latest block")
-	}
-
-	go func() {
-		a.Logger.Info().Msg("Started streaming")
-
-		err := flow.StreamTransactions(ctx, o, pollInterval, startHeight, a.Logger, overflowChannel)
-		if err != nil {
-			if strings.Contains(err.Error(), "context canceled") {
-				a.Logger.Info().Msg("Streaming stopped due to context cancellation")
-			} else {
-				a.Logger.Warn().Err(err).Msg("Streaming encountered an error")
+		if from := a.Config.Indexer.StartHeight; from != 0 && !a.isLocal() {
+			// The stream would take the chain for reset while waiting for blocks that do not exist yet
+			if from > latestBlock.Height {
+				return fmt.Errorf("invalid indexer.start_height %d: the latest block of %s is %d", from, a.Network, latestBlock.Height)
 			}
-		}
-	}()
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				a.Logger.Info().Msg("Block receiver goroutine stopping due to context cancellation")
-				return
-			case br, ok := <-overflowChannel:
-				if !ok {
-					a.Logger.Info().Msg("Channel has been closed. Crawler stopped completely.")
-					return
-				}
-
-				l := br.Logger
-
-				if br.Error != nil {
-					l.Warn().Err(br.Error).Msg("Failed fetching block")
-					continue
-				}
-
-				// Send transactions directly to UI if there are any
-				if len(br.Transactions) > 0 && teaProgram != nil {
-					for _, ot := range br.Transactions {
-
-						// Extract all authorizers
-						authorizers := ot.Authorizers
-						if len(authorizers) == 0 {
-							authorizers = []string{"N/A"}
-						}
-
-						// Extract proposer and payer
-						proposer := "N/A"
-						if ot.ProposalKey.Address.String() != "" {
-							proposer = fmt.Sprintf("0x%s", ot.ProposalKey.Address.Hex())
-						}
-
-						payer := "N/A"
-						if ot.Payer != "" {
-							payer = ot.Payer
-						}
-						// Determine status
-						status := ot.Status
-						if ot.Error != nil {
-							status = "Failed"
-						}
-
-						// Store full script - user can scroll if needed
-						script := string(ot.Script)
-						highlightedScript := chroma.HighlightCadence(script)
-
-						// Format arguments as structured data
-						args := make([]ArgumentData, 0, len(ot.Arguments))
-						for i, arg := range ot.Arguments {
-							// Use the key field as the argument name, fallback to index if not available
-							name := arg.Key
-							if name == "" {
-								name = fmt.Sprintf("argument%d", i)
-							}
-							argData := ArgumentData{
-								Name:  name,
-								Value: arg.Value, // Keep as interface{} for proper formatting
-							}
-							args = append(args, argData)
-						}
-
-						// Create error message
-						errMsg := ""
-						if ot.Error != nil {
-							errMsg = ot.Error.Error()
-						}
-
-						// Store events directly
-						events := ot.Events
-
-						// Detect and decode EVM transactions from events
-						evmTransactions := make([]EVMTransactionData, 0)
-						hasEVMEvents := false
-						hasNonEVMEvents := false
-
-						for _, event := range events {
-							// Check if this is an EVM.TransactionExecuted event
-							if strings.Contains(event.Name, "EVM.TransactionExecuted") {
-								hasEVMEvents = true
-								tx, receipt, payload, err := models.DecodeTransactionEvent(event.RawEvent)
-								if err != nil {
-									// Skip events that fail to decode
-									continue
-								}
-								evmTx := EVMTransactionData{
-									Transaction: tx,
-									Receipt:     receipt,
-									Payload:     payload,
-								}
-								evmTransactions = append(evmTransactions, evmTx)
-							} else {
-								hasNonEVMEvents = true
-							}
-						}
-
-						// Determine transaction type
-						txType := TransactionTypeFlow // Default to flow
-						if hasEVMEvents && !hasNonEVMEvents {
-							txType = TransactionTypeEVM
-						} else if hasEVMEvents && hasNonEVMEvents {
-							txType = TransactionTypeMixed
-						}
-
-						txData := TransactionData{
-							ID:                ot.Id,
-							BlockID:           br.Block.ID.String(),
-							BlockHeight:       br.Block.Height,
-							Authorizers:       authorizers,
-							Status:            status,
-							Proposer:          proposer,
-							Payer:             payer,
-							GasLimit:          ot.GasLimit,
-							Script:            script,
-							HighlightedScript: highlightedScript,
-							Arguments:         args,
-							Events:            events,
-							EVMTransactions:   evmTransactions,
-							Type:              txType,
-							Error:             errMsg,
-							Timestamp:         time.Now(),
-							Index:             ot.TransactionIndex,
-						}
-
-						teaProgram.Send(BlockTransactionMsg{
-							TransactionData: txData,
-						})
-
-						// Send individual event messages
-						for eventIndex, event := range events {
-							eventData := EventData{
-								Name:             event.Name,
-								BlockHeight:      br.Block.Height,
-								BlockID:          br.Block.ID.String(),
-								TransactionID:    ot.Id,
-								TransactionIndex: ot.TransactionIndex,
-								EventIndex:       eventIndex,
-								Fields:           event.Fields,
-								Timestamp:        time.Now(),
-							}
-							teaProgram.Send(BlockEventMsg{
-								EventData: eventData,
-							})
-						}
+			// Backfill from indexer.start_height, the stream starts after the height it is given
+			startHeight = from - 1
+			a.endHeight = a.Config.Indexer.EndHeight
+			to := a.endHeight
+			if to == 0 {
+				to = latestBlock.Height
+			}
+			a.Logger.Info().
+				Str("network", a.Network).
+				Uint64("from", from).
+				Uint64("to", to).
+				Bool("follow", a.endHeight == 0).
+				Msg("Backfilling blocks")
+			if sink != nil {
+				sink.Send(IndexRangeMsg{From: from, To: to, Follow: a.endHeight == 0})
+			}
+		} else {
+			resumed := false
+			if a.Config.Indexer.Checkpoint && !a.isLocal() {
+				a.checkpointFile = flow.CheckpointFile(a.Network)
+				if resume, ok := a.resumeHeight(latestBlock.Height); ok {
+					// Catch up on the blocks since the last run, shown like a backfill
+					startHeight = resume
+					resumed = true
+					if sink != nil && resume < latestBlock.Height {
+						sink.Send(IndexRangeMsg{From: resume + 1, To: latestBlock.Height, Follow: true})
 					}
 				}
-
-				// Send block height update to dashboard
-				teaProgram.Send(BlockHeightMsg{
-					Height: br.Block.Height,
-				})
-
-				// Log the block processing
-				txCount := len(br.Transactions)
-
-				if txCount > 0 {
-					l.Info().
-						Uint64("height", br.Block.Height).
-						Int("txCount", txCount).
-						Msg("Processed block")
-				}
+			}
+			if !resumed {
+				a.Logger.Info().
+					Str("network", a.Network).
+					Uint64("startHeight", startHeight).
+					Dur("pollInterval", a.Config.Indexer.PollingInterval).
+					Msg("Starting to stream from latest block")
 			}
 		}
-	}()
+	}
+
+	a.loadHistory(ctx)
+	a.startHeight = startHeight
+	a.startIndexer(startHeight)
+
+	if restored {
+		a.Logger.Info().Str("dbPath", a.Config.Flow.DBPath).Msg("Restored persisted emulator state - skipping deploy and init transactions")
+		a.registerEVMDeployments()
+		a.setupDone()
+		return nil
+	}
 
 	// Only perform local setup in emulator mode
-	if a.Network == "emulator" {
+	if a.isLocal() {
 		a.Logger.Info().Msgf("%v Created accounts for emulator users in flow.json", emoji.Person)
-		o.InitializeContracts(ctx)
-
-		a.Logger.Info().Msgf("%v  Deployed contracts specified in emulator deployment block", emoji.Envelope)
-		if err := flow.AddFclContract(o, a.FclCdc); err != nil {
+		if err := a.deployContracts(ctx, o); err != nil {
 			return err
 		}
-
-		accounts := o.GetEmulatorAccounts()
-		a.Logger.Debug().Int("filtered_accounts", len(accounts)).Interface("accounts", accounts).Msg("Filtered emulator accounts")
-
-		if len(accounts) > 0 {
-			a.Logger.Info().Int("count", len(accounts)).Interface("accounts", accounts).Msgf("%v Adding accounts to FCL", emoji.Person)
-			if err := flow.AddFclAccounts(o, accounts); err != nil {
-				return err
-			}
-			a.Logger.Info().Msgf("%v Successfully added %d accounts to FCL", emoji.Person, len(accounts))
-		} else {
-			a.Logger.Warn().Msg("No accounts to add to FCL")
+		if err := a.fundEVMAccounts(o); err != nil {
+			return err
+		}
+		if err := a.deployEVMContracts(ctx); err != nil {
+			return err
 		}
 
 		// Determine init transactions path - either from config or interactive selection
@@ -486,17 +404,17 @@ func (a *Aether) Start(teaProgram *tea.Program) error {
 				Msg("Found init transaction folders, prompting user for selection")
 			
 			// Send folder selection message to UI
-			teaProgram.Send(InitFolderSelectionMsg{
+			sink.Send(InitFolderSelectionMsg{
 				Folders:     folders,
 				DefaultPath: validPath,
 			})
 			
 			// Store context for deferred init transaction execution
 			a.pendingInitTx = &pendingInitContext{
-				teaProgram: teaProgram,
-				o:          o,
-				oR:         oR,
-				basePath:   validPath,
+				sink:     sink,
+				o:        o,
+				oR:       oR,
+				basePath: validPath,
 			}
 			a.Logger.Info().Msg("Init transaction context stored, waiting for user folder selection...")
 			
@@ -508,25 +426,10 @@ func (a *Aether) Start(teaProgram *tea.Program) error {
 			}
 
 			// Use same overflow state for both .cdc and .json files
-			if err := flow.RunInitTransactions(o, oR, initTxPath, a.Logger, func(filename string, success bool, errorMsg string, txID string) {
-				// Send progress update to UI
-				teaProgram.Send(InitTransactionMsg{
-					Filename:      filename,
-					Success:       success,
-					Error:         errorMsg,
-					TransactionID: txID,
-				})
-				// Send transaction source tracking
-				if success && txID != "" {
-					teaProgram.Send(TransactionSourceMsg{
-						TransactionID: txID,
-						SourceFile:    filename,
-						IsInit:        true,
-					})
-				}
-			}); err != nil {
+			if err := flow.RunInitTransactions(o, oR, initTxPath, a.Logger, initProgressCallback(sink)); err != nil {
 				return err
 			}
+			a.setupDone()
 		}
 	} else {
 		a.Logger.Info().Str("network", a.Network).Msg("Following network - skipping local setup steps")
@@ -534,7 +437,102 @@ func (a *Aether) Start(teaProgram *tea.Program) error {
 	return nil
 }
 
+// deployContracts deploys the contracts in the deployment block and registers the accounts with the dev wallet
+func (a *Aether) deployContracts(ctx context.Context, o *overflow.OverflowState) error {
+	o.InitializeContracts(ctx)
+
+	a.Logger.Info().Msgf("%v  Deployed contracts specified in emulator deployment block", emoji.Envelope)
+	if err := flow.AddFclContract(o, a.FclCdc); err != nil {
+		return err
+	}
+
+	accounts := o.GetEmulatorAccounts()
+	a.Logger.Debug().Int("filtered_accounts", len(accounts)).Interface("accounts", accounts).Msg("Filtered emulator accounts")
+
+	if len(accounts) > 0 {
+		a.Logger.Info().Int("count", len(accounts)).Interface("accounts", accounts).Msgf("%v Adding accounts to FCL", emoji.Person)
+		if err := flow.AddFclAccounts(o, accounts); err != nil {
+			return err
+		}
+		a.Logger.Info().Msgf("%v Successfully added %d accounts to FCL", emoji.Person, len(accounts))
+	} else {
+		a.Logger.Warn().Msg("No accounts to add to FCL")
+	}
+	return nil
+}
+
+// setupDone is called once the local setup is in place, either deployed and initialized or restored
+func (a *Aether) setupDone() {
+	// Init transactions may have created COAs
+	a.registerCOAs(a.Overflow)
+	a.enterManualBlocks()
+	a.startContractWatcher()
+}
+
+// Stop stops streaming transactions from the network or emulator
 func (a *Aether) Stop() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.saveCheckpoint(0, true)
+}
+
+// DetectBasePath finds the aether folder, either aether or cadence/aether.
+// It returns the aether folder and the base path overflow resolves files from ("" or "cadence")
+func DetectBasePath() (validPath string, basePath string, err error) {
+	// Define the two possible paths
+	path1 := "aether"
+	path2 := filepath.Join("cadence", "aether")
+
+	// Check which path exists
+	if _, err := os.Stat(path1); err == nil {
+		return path1, "", nil
+	}
+	if _, err := os.Stat(path2); err == nil {
+		return path2, "cadence", nil
+	}
+	return "", "", fmt.Errorf("neither %q nor %q exists", path1, path2)
+}
+
+// underflowOptions configures human-friendly output for values decoded by overflow
+var underflowOptions = underflow.Options{
+	ByteArrayAsHex:             true,
+	ShowUnixTimestampsAsString: true,
+	TimestampFormat:            "2006-01-02 15:04:05 UTC",
+}
+
+// NewRunnerOverflow creates the overflow instance used to run scripts and transactions by name
+// It resolves files from <basePath>/scripts and <basePath>/transactions like the runner view does
+func NewRunnerOverflow(cfg *config.Config, basePath string) *overflow.OverflowState {
+	return overflow.Overflow(append(networkOptions(cfg),
+		overflow.WithLogNone(),
+		overflow.WithReturnErrors(),
+		overflow.WithBasePath(basePath),
+		overflow.WithUnderflowOptions(underflowOptions))...)
+}
+
+// networkOptions selects the network overflow talks to. A fork uses the accounts and aliases of the
+// forked network in flow.json but sends everything to the local emulator.
+func networkOptions(cfg *config.Config) []overflow.OverflowOption {
+	if cfg.Network == "emulator" {
+		// The host in flow.json is not used, ports.offset or ports.auto can move the emulator
+		return []overflow.OverflowOption{
+			overflow.WithExistingEmulator(),
+			overflow.WithNetworkHost(fmt.Sprintf("127.0.0.1:%d", cfg.Ports.Emulator.GRPC)),
+		}
+	}
+	if fork, ok, _ := config.ParseFork(cfg.Network); ok {
+		return []overflow.OverflowOption{
+			overflow.WithNetwork(fork.Network),
+			overflow.WithNetworkHost(fmt.Sprintf("127.0.0.1:%d", cfg.Ports.Emulator.GRPC)),
+		}
+	}
+	return []overflow.OverflowOption{overflow.WithNetwork(cfg.Network)}
+}
+
+// isLocal reports whether aether runs the local emulator, either fresh or forked
+func (a *Aether) isLocal() bool {
+	return config.IsEmulator(a.Network)
 }
 
 // scanInitFolders scans the base aether directory for subdirectories
@@ -569,7 +567,7 @@ func (a *Aether) RunInitTransactionsWithFolder(selectedFolder string) error {
 	}
 	
 	ctx := a.pendingInitTx
-	teaProgram := ctx.teaProgram
+	sink := ctx.sink
 	o := ctx.o
 	oR := ctx.oR
 	basePath := ctx.basePath
@@ -584,9 +582,25 @@ func (a *Aether) RunInitTransactionsWithFolder(selectedFolder string) error {
 		Msg("Running init transactions from selected folder")
 	
 	// Run init transactions
-	if err := flow.RunInitTransactions(o, oR, initTxPath, a.Logger, func(filename string, success bool, errorMsg string, txID string) {
+	if err := flow.RunInitTransactions(o, oR, initTxPath, a.Logger, initProgressCallback(sink)); err != nil {
+		a.Logger.Error().Err(err).Msg("Failed to run init transactions")
+		return err
+	}
+	
+	a.initPath = initTxPath
+	a.Logger.Info().Msg("Init transactions completed")
+	a.setupDone()
+	return nil
+}
+
+// initProgressCallback reports init transaction progress and source tracking to the sink
+func initProgressCallback(sink events.Sink) func(string, bool, string, string) {
+	return func(filename string, success bool, errorMsg string, txID string) {
+		if sink == nil {
+			return
+		}
 		// Send progress update to UI
-		teaProgram.Send(InitTransactionMsg{
+		sink.Send(InitTransactionMsg{
 			Filename:      filename,
 			Success:       success,
 			Error:         errorMsg,
@@ -594,17 +608,11 @@ func (a *Aether) RunInitTransactionsWithFolder(selectedFolder string) error {
 		})
 		// Send transaction source tracking
 		if success && txID != "" {
-			teaProgram.Send(TransactionSourceMsg{
+			sink.Send(TransactionSourceMsg{
 				TransactionID: txID,
 				SourceFile:    filename,
 				IsInit:        true,
 			})
 		}
-	}); err != nil {
-		a.Logger.Error().Err(err).Msg("Failed to run init transactions")
-		return err
 	}
-	
-	a.Logger.Info().Msg("Init transactions completed")
-	return nil
 }
@@ -0,0 +1,206 @@
+package aether
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bjartek/overflow/v2"
+)
+
+// workingSnapshotPrefix names the copies aether works on after a revert.
+// Loading a snapshot makes the emulator write to it, so we load a copy to keep the named snapshot unchanged.
+const workingSnapshotPrefix = "aether-working-"
+
+// snapshotFilePrefix is the file name prefix the emulator uses for persisted snapshots
+const snapshotFilePrefix = "snapshot_"
+
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// SnapshotsEnabled reports whether snapshots can be managed, i.e. we run a local emulator with snapshots enabled
+func (a *Aether) SnapshotsEnabled() bool {
+	return a.Emulator != nil && a.Config != nil && a.Config.Flow.Snapshot
+}
+
+// Snapshots returns the names of all named snapshots, sorted
+func (a *Aether) Snapshots() ([]string, error) {
+	if !a.SnapshotsEnabled() {
+		return nil, fmt.Errorf("snapshots are not enabled")
+	}
+
+	all, err := a.Emulator.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range all {
+		if strings.HasPrefix(name, workingSnapshotPrefix) || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateSnapshot stores the current emulator state under the given name
+func (a *Aether) CreateSnapshot(name string) error {
+	if err := a.checkSnapshotName(name); err != nil {
+		return err
+	}
+
+	existing, err := a.Snapshots()
+	if err != nil {
+		return err
+	}
+	for _, n := range existing {
+		if n == name {
+			return fmt.Errorf("snapshot %q already exists", name)
+		}
+	}
+
+	if err := a.Emulator.CreateSnapshot(name); err != nil {
+		return fmt.Errorf("failed to create snapshot %q: %w", name, err)
+	}
+	a.Logger.Info().Str("snapshot", name).Msg("Created snapshot")
+	return nil
+}
+
+// RevertToSnapshot replaces the emulator state with the given snapshot and re-indexes all blocks
+func (a *Aether) RevertToSnapshot(name string) error {
+	if err := a.checkSnapshotName(name); err != nil {
+		return err
+	}
+
+	previous, err := a.workingSnapshots()
+	if err != nil {
+		return err
+	}
+
+	if err := a.Emulator.LoadSnapshot(name); err != nil {
+		return fmt.Errorf("failed to load snapshot %q: %w", name, err)
+	}
+
+	// Continue on a copy so that the named snapshot can be reverted to again
+	working := fmt.Sprintf("%s%d", workingSnapshotPrefix, time.Now().UnixNano())
+	if err := a.Emulator.CreateSnapshot(working); err != nil {
+		return fmt.Errorf("failed to copy snapshot %q: %w", name, err)
+	}
+	if err := a.Emulator.LoadSnapshot(working); err != nil {
+		return fmt.Errorf("failed to load copy of snapshot %q: %w", name, err)
+	}
+
+	// Older working copies are no longer in use
+	for _, old := range previous {
+		a.removeSnapshotFile(old)
+	}
+
+	a.Logger.Info().Str("snapshot", name).Msg("Reverted to snapshot")
+	a.Reindex()
+	return nil
+}
+
+// DeleteSnapshot removes a named snapshot. Only persisted snapshots can be deleted,
+// in-memory snapshots live until the emulator stops.
+func (a *Aether) DeleteSnapshot(name string) error {
+	if err := a.checkSnapshotName(name); err != nil {
+		return err
+	}
+	if !a.Config.Flow.Persist {
+		return fmt.Errorf("in-memory snapshots cannot be deleted, enable flow.persist to manage snapshots on disk")
+	}
+
+	path := filepath.Join(a.Config.Flow.DBPath, snapshotFilePrefix+name)
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot %q does not exist", name)
+		}
+		return fmt.Errorf("failed to delete snapshot %q: %w", name, err)
+	}
+	a.Logger.Info().Str("snapshot", name).Msg("Deleted snapshot")
+	return nil
+}
+
+// resumeWorkingSnapshot loads the newest working copy on startup, so that a revert done in
+// a previous run is not lost when the emulator opens its main database again
+func (a *Aether) resumeWorkingSnapshot() error {
+	if !a.SnapshotsEnabled() {
+		return nil
+	}
+
+	working, err := a.workingSnapshots()
+	if err != nil || len(working) == 0 {
+		return err
+	}
+
+	latest := working[len(working)-1]
+	if err := a.Emulator.LoadSnapshot(latest); err != nil {
+		return fmt.Errorf("failed to resume snapshot state: %w", err)
+	}
+	a.Logger.Info().Str("snapshot", latest).Msg("Resumed emulator state from last revert")
+
+	for _, old := range working[:len(working)-1] {
+		a.removeSnapshotFile(old)
+	}
+	return nil
+}
+
+// workingSnapshots returns the working copies, oldest first
+func (a *Aether) workingSnapshots() ([]string, error) {
+	all, err := a.Emulator.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	var working []string
+	for _, name := range all {
+		if strings.HasPrefix(name, workingSnapshotPrefix) {
+			working = append(working, name)
+		}
+	}
+	sort.Strings(working)
+	return working, nil
+}
+
+// removeSnapshotFile deletes a persisted snapshot, in-memory snapshots are left alone
+func (a *Aether) removeSnapshotFile(name string) {
+	if !a.Config.Flow.Persist {
+		return
+	}
+	path := filepath.Join(a.Config.Flow.DBPath, snapshotFilePrefix+name)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		a.Logger.Warn().Err(err).Str("snapshot", name).Msg("Failed to remove old working snapshot")
+	}
+}
+
+func (a *Aether) checkSnapshotName(name string) error {
+	if !a.SnapshotsEnabled() {
+		return fmt.Errorf("snapshots are not enabled")
+	}
+	if !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: use letters, numbers, '.', '-' and '_'", name)
+	}
+	if strings.HasPrefix(name, workingSnapshotPrefix) {
+		return fmt.Errorf("snapshot names starting with %q are reserved", workingSnapshotPrefix)
+	}
+	return nil
+}
+
+// hasPersistedState reports whether the emulator already has the setup from a previous run,
+// which is the case when the FCL contract has been deployed to the service account
+func hasPersistedState(ctx context.Context, o *overflow.OverflowState) bool {
+	account, err := o.GetAccount(ctx, o.ServiceAccountSuffix)
+	if err != nil {
+		return false
+	}
+	_, ok := account.Contracts["FCL"]
+	return ok
+}
@@ -0,0 +1,112 @@
+package aether
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+// travelClock is the emulator clock. It runs offset ahead of the wall clock so block timestamps can be moved forward.
+type travelClock struct {
+	offset atomic.Int64
+}
+
+// Now implements emulator.Clock
+func (c *travelClock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns how far the clock is ahead of the wall clock
+func (c *travelClock) Offset() time.Duration {
+	return time.Duration(c.offset.Load())
+}
+
+// clockSetter is implemented by the emulator blockchain, it is not part of the emulator.Emulator interface
+type clockSetter interface {
+	SetClock(clock emulator.Clock)
+}
+
+// TimeTravelEnabled reports whether the emulator block timestamps can be moved forward
+func (a *Aether) TimeTravelEnabled() bool {
+	return a.clock != nil
+}
+
+// startClock lets the emulator use a clock that can be moved forward.
+// With persisted state the last block can be ahead of the wall clock, the clock continues from there.
+func (a *Aether) startClock() {
+	setter, ok := a.Emulator.(clockSetter)
+	if !ok {
+		a.Logger.Warn().Msg("Emulator does not support setting the clock, time travel is disabled")
+		return
+	}
+
+	clock := &travelClock{}
+	if block, err := a.Emulator.GetLatestBlock(); err == nil {
+		if ahead := time.Until(time.UnixMilli(int64(block.Timestamp))); ahead > 0 {
+			clock.offset.Store(int64(ahead))
+			a.Logger.Info().Dur("ahead", ahead).Msg("Latest block is ahead of the wall clock, continuing from its timestamp")
+		}
+	}
+	setter.SetClock(clock)
+	a.clock = clock
+}
+
+// EmulatedTime returns the time the next emulator block gets as timestamp
+func (a *Aether) EmulatedTime() time.Time {
+	if a.clock == nil {
+		return time.Now()
+	}
+	return a.clock.Now()
+}
+
+// AdvanceTime moves the emulator clock forward by d and commits a block with the new timestamp,
+// so contracts and scheduled transactions see the new time right away.
+func (a *Aether) AdvanceTime(d time.Duration) (CommittedBlock, error) {
+	if !a.TimeTravelEnabled() {
+		return CommittedBlock{}, fmt.Errorf("time travel is only available on the local emulator")
+	}
+	if d <= 0 {
+		return CommittedBlock{}, fmt.Errorf("invalid duration %s: must be positive", d)
+	}
+
+	a.clock.offset.Add(int64(d))
+	// Setting the clock again moves the timestamp of the pending block
+	a.Emulator.(clockSetter).SetClock(a.clock)
+	a.Logger.Info().Dur("by", d).Dur("ahead", a.clock.Offset()).Msg("Advanced emulator time")
+
+	return a.CommitBlocks(1)
+}
+
+// ParseTravelDuration parses a duration like time.ParseDuration, with days as an extra unit, e.g. 7d or 1d12h
+func ParseTravelDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	rest := s
+
+	var days time.Duration
+	if i := strings.Index(rest, "d"); i >= 0 {
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		rest = rest[i+1:]
+	}
+
+	var d time.Duration
+	if rest != "" {
+		var err error
+		d, err = time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+
+	if days+d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q: must be positive", s)
+	}
+	return days + d, nil
+}
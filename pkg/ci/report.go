@@ -0,0 +1,183 @@
+package ci
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TestCase is a single init transaction or scenario step
+type TestCase struct {
+	Name          string        `json:"name"`
+	Success       bool          `json:"success"`
+	Error         string        `json:"error,omitempty"`
+	TransactionID string        `json:"transactionId,omitempty"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// Suite groups test cases, e.g. the init transactions or one scenario
+type Suite struct {
+	Name  string     `json:"name"`
+	Cases []TestCase `json:"cases"`
+}
+
+// Failures returns the number of failed cases in the suite
+func (s Suite) Failures() int {
+	failures := 0
+	for _, c := range s.Cases {
+		if !c.Success {
+			failures++
+		}
+	}
+	return failures
+}
+
+// Duration returns the total duration of the suite
+func (s Suite) Duration() time.Duration {
+	var d time.Duration
+	for _, c := range s.Cases {
+		d += c.Duration
+	}
+	return d
+}
+
+// Report is the outcome of an `aether ci` run
+type Report struct {
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	Suites    []Suite       `json:"suites"`
+}
+
+// Finish marks the report as done. It is successful if there is no error and every case passed
+func (r *Report) Finish(err error) {
+	r.Duration = time.Since(r.StartedAt)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	r.Success = r.Error == ""
+	for _, s := range r.Suites {
+		if s.Failures() > 0 {
+			r.Success = false
+		}
+	}
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report in the JUnit XML format understood by most CI systems.
+// The transaction ID of each case is written to system-out.
+func (r *Report) WriteJUnit(path string) error {
+	data, err := r.MarshalJUnit()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// MarshalJUnit renders the report as JUnit XML
+func (r *Report) MarshalJUnit() ([]byte, error) {
+	root := junitTestSuites{
+		Name: "aether",
+		Time: seconds(r.Duration),
+	}
+
+	for _, s := range r.Suites {
+		suite := junitTestSuite{
+			Name:      s.Name,
+			Tests:     len(s.Cases),
+			Failures:  s.Failures(),
+			Time:      seconds(s.Duration()),
+			Timestamp: r.StartedAt.UTC().Format("2006-01-02T15:04:05"),
+		}
+		for _, c := range s.Cases {
+			tc := junitTestCase{
+				Name:      c.Name,
+				ClassName: s.Name,
+				Time:      seconds(c.Duration),
+			}
+			if c.TransactionID != "" {
+				tc.SystemOut = fmt.Sprintf("transaction id: %s", c.TransactionID)
+			}
+			if !c.Success {
+				tc.Failure = &junitFailure{
+					Message: c.Error,
+					Content: c.Error,
+				}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Suites = append(root.Suites, suite)
+	}
+
+	// An error outside of any case (e.g. emulator failed to start) is reported as an error
+	if r.Error != "" {
+		root.Errors = 1
+		root.Tests++
+		root.Suites = append(root.Suites, junitTestSuite{
+			Name:  "aether",
+			Tests: 1,
+			Time:  "0.000",
+			Cases: []junitTestCase{{
+				Name:      "setup",
+				ClassName: "aether",
+				Time:      "0.000",
+				Failure:   &junitFailure{Message: r.Error, Content: r.Error},
+			}},
+		})
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
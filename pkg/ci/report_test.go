@@ -0,0 +1,108 @@
+package ci
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	return &Report{
+		StartedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Suites: []Suite{
+			{
+				Name: "init transactions",
+				Cases: []TestCase{
+					{Name: "1_foo_alice", Success: true, TransactionID: "abc123", Duration: 1500 * time.Millisecond},
+					{Name: "2_foo_bob", Success: false, Error: "cadence panic <boom>", Duration: 500 * time.Millisecond},
+				},
+			},
+		},
+	}
+}
+
+func TestFinish(t *testing.T) {
+	r := testReport()
+	r.Finish(nil)
+	if r.Success {
+		t.Errorf("expected report with failed case to be unsuccessful")
+	}
+
+	r = &Report{StartedAt: time.Now(), Suites: []Suite{{Name: "ok", Cases: []TestCase{{Name: "a", Success: true}}}}}
+	r.Finish(nil)
+	if !r.Success {
+		t.Errorf("expected report without failures to be successful")
+	}
+
+	r.Finish(errors.New("emulator did not start"))
+	if r.Success {
+		t.Errorf("expected report with error to be unsuccessful")
+	}
+}
+
+func TestMarshalJUnit(t *testing.T) {
+	r := testReport()
+	r.Finish(nil)
+
+	data, err := r.MarshalJUnit()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := string(data)
+
+	expected := []string{
+		`<testsuites name="aether" tests="2" failures="1" errors="0"`,
+		`<testsuite name="init transactions" tests="2" failures="1" time="2.000" timestamp="2025-01-02T03:04:05">`,
+		`<testcase name="1_foo_alice" classname="init transactions" time="1.500">`,
+		`<system-out>transaction id: abc123</system-out>`,
+		`<failure message="cadence panic &lt;boom&gt;">cadence panic &lt;boom&gt;</failure>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("expected junit output to contain %q, got:\n%s", e, out)
+		}
+	}
+}
+
+func TestMarshalJUnitWithError(t *testing.T) {
+	r := &Report{StartedAt: time.Now()}
+	r.Finish(errors.New("failed to deploy contracts"))
+
+	data, err := r.MarshalJUnit()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `<failure message="failed to deploy contracts">`) {
+		t.Errorf("expected setup failure in junit output, got:\n%s", data)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := testReport()
+	r.Finish(nil)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := r.WriteJSON(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+
+	var loaded Report
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	if loaded.Suites[0].Cases[0].TransactionID != "abc123" {
+		t.Errorf("expected transaction id abc123, got %q", loaded.Suites[0].Cases[0].TransactionID)
+	}
+	if loaded.Success {
+		t.Errorf("expected success to be false")
+	}
+}
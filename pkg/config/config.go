@@ -16,20 +16,90 @@ type Config struct {
 	FrontendCommand string        `mapstructure:"frontend_command" validate:"omitempty"` // Command to run the frontend process
 }
 
+// ManualBlockTime can be used as flow.block_time instead of setting flow.manual_blocks
+const ManualBlockTime = "manual"
+
 // FlowConfig contains Flow blockchain settings
 type FlowConfig struct {
-	NewUserBalance              float64       `mapstructure:"new_user_balance"`
-	BlockTime                   time.Duration `mapstructure:"block_time"`
-	InitTransactionsFolder      string        `mapstructure:"init_transactions_folder"`      // Folder to scan for init transactions (relative to aether folder)
-	InitTransactionsInteractive bool          `mapstructure:"init_transactions_interactive"` // If true, prompt user to select folder at startup
+	NewUserBalance              float64         `mapstructure:"new_user_balance"`
+	BlockTime                   time.Duration   `mapstructure:"block_time"`                    // 0 commits a block after every transaction
+	ManualBlocks                bool            `mapstructure:"manual_blocks"`                 // If true, blocks are only committed on demand
+	InitTransactionsFolder      string          `mapstructure:"init_transactions_folder"`      // Folder to scan for init transactions (relative to aether folder)
+	InitTransactionsInteractive bool            `mapstructure:"init_transactions_interactive"` // If true, prompt user to select folder at startup
+	Persist                     bool            `mapstructure:"persist"`                       // If true, emulator state is stored in db_path and survives restarts
+	Snapshot                    bool            `mapstructure:"snapshot"`                      // If true, named snapshots can be created and reverted to from the dashboard
+	DBPath                      string          `mapstructure:"db_path"`                       // Folder for the persisted emulator state and snapshots
+	Emulator                    EmulatorConfig  `mapstructure:"emulator"`
+	Fork                        ForkConfig      `mapstructure:"fork"` // Used when network is emulator-fork:<network>@<height>
+	Coverage                    CoverageConfig  `mapstructure:"coverage"`
+	HotReload                   HotReloadConfig `mapstructure:"hot_reload"`
+}
+
+// HotReloadConfig contains settings for redeploying contracts on the local emulator when their source changes
+type HotReloadConfig struct {
+	Enabled            bool     `mapstructure:"enabled"`             // If true, the contracts in the emulator deployment are watched and redeployed on save
+	RemoveIncompatible bool     `mapstructure:"remove_incompatible"` // If true, a contract whose update is rejected is removed and deployed again, losing its stored state
+	InitTransactions   []string `mapstructure:"init_transactions"`   // Init transactions to run again after a redeploy, file names in the init transactions folder
+}
+
+// CoverageConfig contains settings for Cadence code coverage of the local emulator
+type CoverageConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // If true, the emulator collects line coverage of the deployed contracts
+	Folder  string `mapstructure:"folder"`  // Folder the LCOV and JSON coverage reports are written to
+}
+
+// ForkConfig contains settings for forking a live network into the local emulator
+type ForkConfig struct {
+	AccessNode string `mapstructure:"access_node"` // gRPC access node (host:port) to fetch state from, empty uses the public node of the forked network
+}
+
+// EmulatorConfig contains tuning options for the embedded Flow emulator
+type EmulatorConfig struct {
+	TransactionMaxGasLimit       uint64  `mapstructure:"transaction_max_gas_limit"`
+	ScriptGasLimit               uint64  `mapstructure:"script_gas_limit"`
+	StorageLimitEnabled          bool    `mapstructure:"storage_limit_enabled"`
+	TransactionFeesEnabled       bool    `mapstructure:"transaction_fees_enabled"`
+	SimpleAddressesEnabled       bool    `mapstructure:"simple_addresses_enabled"`
+	GenesisTokenSupply           float64 `mapstructure:"genesis_token_supply"`
+	TransactionExpiry            uint    `mapstructure:"transaction_expiry"` // Number of blocks before a transaction expires
+	ContractRemovalEnabled       bool    `mapstructure:"contract_removal_enabled"`
+	SetupEVMEnabled              bool    `mapstructure:"setup_evm_enabled"`
+	ScheduledTransactionsEnabled bool    `mapstructure:"scheduled_transactions_enabled"`
 }
 
 // IndexerConfig contains indexer-specific settings
 type IndexerConfig struct {
-	PollingInterval time.Duration   `mapstructure:"polling_interval"`
-	Underflow       UnderflowConfig `mapstructure:"underflow"`
+	PollingInterval  time.Duration   `mapstructure:"polling_interval"`
+	Backend          string          `mapstructure:"backend"`           // polling or subscription, subscription falls back to polling on error
+	SubscriptionHost string          `mapstructure:"subscription_host"` // Access API host for subscriptions, empty is the host of the network
+	StartHeight      uint64          `mapstructure:"start_height"`      // First block to index when following a network, 0 is the latest block
+	EndHeight        uint64          `mapstructure:"end_height"`        // Last block to index, 0 keeps following the network
+	Checkpoint       bool            `mapstructure:"checkpoint"`        // Resume following a network after the last indexed block of the previous run
+	MaxCatchUp       uint64          `mapstructure:"max_catch_up"`      // Most blocks to catch up on when resuming, 0 is no limit
+	Concurrency      int             `mapstructure:"concurrency"`       // Blocks fetched in parallel when catching up, 1 fetches one at a time
+	Filter           IndexerFilter   `mapstructure:"filter"`            // Only keep the transactions that touch what is watched
+	Underflow        UnderflowConfig `mapstructure:"underflow"`
 }
 
+// IndexerFilter is a watchlist for the indexer, a transaction is kept if it matches any entry.
+// An empty filter keeps every transaction.
+type IndexerFilter struct {
+	Addresses []string `mapstructure:"addresses"` // Flow addresses signing as authorizer, payer or proposer
+	Contracts []string `mapstructure:"contracts"` // Contract identifiers like A.1654653399040a61.FlowToken, imported or emitting events
+	Events    []string `mapstructure:"events"`    // Event type prefixes like A.1654653399040a61.FlowToken.TokensDeposited
+}
+
+// IsEmpty reports whether the filter keeps every transaction
+func (f IndexerFilter) IsEmpty() bool {
+	return len(f.Addresses) == 0 && len(f.Contracts) == 0 && len(f.Events) == 0
+}
+
+// Indexer backends
+const (
+	IndexerBackendPolling      = "polling"
+	IndexerBackendSubscription = "subscription"
+)
+
 // UnderflowConfig contains data formatting options
 type UnderflowConfig struct {
 	ByteArrayAsHex       bool   `mapstructure:"byte_array_as_hex"`
@@ -42,6 +112,9 @@ type PortsConfig struct {
 	Emulator  EmulatorPortsConfig `mapstructure:"emulator"`
 	DevWallet int                 `mapstructure:"dev_wallet"`
 	EVM       EVMPortsConfig      `mapstructure:"evm"`
+	Control   int                 `mapstructure:"control"` // Local control API used by the aether CLI, e.g. aether commit
+	Auto      bool                `mapstructure:"auto"`    // If true, ports that are in use are replaced with free ones on start
+	Offset    int                 `mapstructure:"offset"`  // Added to every port, e.g. 100 to run a second project next to the first
 }
 
 // EmulatorPortsConfig contains emulator-specific ports
@@ -61,8 +134,40 @@ type EVMPortsConfig struct {
 
 // EVMConfig contains EVM gateway settings
 type EVMConfig struct {
-	DatabasePath          string `mapstructure:"database_path"`
-	DeleteDatabaseOnStart bool   `mapstructure:"delete_database_on_start"`
+	DatabasePath           string              `mapstructure:"database_path"`
+	DeleteDatabaseOnStart  bool                `mapstructure:"delete_database_on_start"`
+	ChainID                uint64              `mapstructure:"chain_id"`                  // EVM chain ID, 646 is the Flow EVM preview net
+	GasPrice               uint64              `mapstructure:"gas_price"`                 // Gas price in attoflow the gateway submits transactions with
+	EnforceGasPrice        bool                `mapstructure:"enforce_gas_price"`         // If true, transactions below gas_price are rejected
+	TxStateValidation      string              `mapstructure:"tx_state_validation"`       // local-index or tx-seal
+	WalletEnabled          bool                `mapstructure:"wallet_enabled"`            // If true, the gateway signs eth_sendTransaction with wallet_key
+	WalletKey              string              `mapstructure:"wallet_key"`                // Hex secp256k1 key of the wallet API, its address is the coinbase
+	COAAccount             string              `mapstructure:"coa_account"`               // flow.json account the gateway submits Flow transactions from, empty is the emulator service account
+	COAKeyFile             string              `mapstructure:"coa_key_file"`              // File with the hex private key of the COA account, empty uses the key in flow.json
+	FilterExpiry           time.Duration       `mapstructure:"filter_expiry"`             // Idle time after which a filter expires
+	TxRequestLimitDuration time.Duration       `mapstructure:"tx_request_limit_duration"` // Interval of the transaction submission rate limit
+	TxBatchInterval        time.Duration       `mapstructure:"tx_batch_interval"`         // Interval transaction batches are submitted in
+	EOAActivityCacheTTL    time.Duration       `mapstructure:"eoa_activity_cache_ttl"`    // How long EOA activity is tracked for batching
+	Accounts               []EVMAccountConfig  `mapstructure:"accounts"`                  // Named EOAs funded with FLOW when the emulator starts
+	Contracts              []EVMContractConfig `mapstructure:"contracts"`                 // Solidity contracts deployed in order once the gateway is ready
+	DeploymentsFile        string              `mapstructure:"deployments_file"`          // JSON file the addresses of the deployed contracts are written to
+}
+
+// EVMAccountConfig is a named EVM externally owned account that is funded when the emulator starts
+type EVMAccountConfig struct {
+	Name           string  `mapstructure:"name"`            // Shown as <name>-evm instead of the address
+	PrivateKey     string  `mapstructure:"private_key"`     // Hex secp256k1 key
+	Mnemonic       string  `mapstructure:"mnemonic"`        // BIP-39 mnemonic, instead of private_key
+	DerivationPath string  `mapstructure:"derivation_path"` // Used with mnemonic, empty is m/44'/60'/0'/0/0
+	Balance        float64 `mapstructure:"balance"`         // FLOW to fund the account with, 0 uses flow.new_user_balance
+}
+
+// EVMContractConfig is a compiled Solidity contract that is deployed when the gateway is ready
+type EVMContractConfig struct {
+	Name     string   `mapstructure:"name"`     // Shown instead of the address, and usable in the args of later contracts
+	Artifact string   `mapstructure:"artifact"` // Foundry (out/) or Hardhat (artifacts/) JSON file
+	Args     []string `mapstructure:"args"`     // Constructor arguments, an address argument can be an evm account or contract name
+	Deployer string   `mapstructure:"deployer"` // evm account that deploys the contract, empty is the first evm account or the gateway wallet
 }
 
 // LoggingConfig contains logging settings
@@ -98,9 +203,11 @@ type UIConfig struct {
 
 // HistoryConfig contains history limits
 type HistoryConfig struct {
-	MaxTransactions int `mapstructure:"max_transactions"`
-	MaxEvents       int `mapstructure:"max_events"`
-	MaxLogLines     int `mapstructure:"max_log_lines"`
+	MaxTransactions int  `mapstructure:"max_transactions"`
+	MaxEvents       int  `mapstructure:"max_events"`
+	MaxLogLines     int  `mapstructure:"max_log_lines"`
+	Persist         bool `mapstructure:"persist"`    // Keep the history in .aether/history/<network> and reload it on start
+	MaxStored       int  `mapstructure:"max_stored"` // Transactions, events and log lines each kept on disk
 }
 
 // LayoutConfig contains layout preferences
@@ -57,6 +57,10 @@ func TestLoadWithConfigFile(t *testing.T) {
 
 	configContent := `
 network: testnet
+flow:
+  emulator:
+    transaction_max_gas_limit: 999999
+    storage_limit_enabled: false
 indexer:
   polling_interval: 500ms
 ports:
@@ -94,6 +98,18 @@ logging:
 		t.Errorf("expected global log level 'debug', got '%s'", cfg.Logging.Level.Global)
 	}
 
+	if cfg.Flow.Emulator.TransactionMaxGasLimit != 999999 {
+		t.Errorf("expected transaction max gas limit 999999, got %d", cfg.Flow.Emulator.TransactionMaxGasLimit)
+	}
+
+	if cfg.Flow.Emulator.StorageLimitEnabled {
+		t.Errorf("expected storage limit to be disabled")
+	}
+
+	if cfg.Flow.Emulator.ScriptGasLimit != 100000 {
+		t.Errorf("expected default script gas limit 100000, got %d", cfg.Flow.Emulator.ScriptGasLimit)
+	}
+
 	// Verify defaults are still applied for non-overridden values
 	if cfg.Ports.DevWallet != 8701 {
 		t.Errorf("expected default dev_wallet port 8701, got %d", cfg.Ports.DevWallet)
@@ -118,6 +134,28 @@ func TestValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "fork mainnet at height",
+			modify: func(c *Config) {
+				c.Network = "emulator-fork:mainnet@123456"
+			},
+			wantErr: false,
+		},
+		{
+			name: "fork unknown network",
+			modify: func(c *Config) {
+				c.Network = "emulator-fork:previewnet@1"
+			},
+			wantErr: true,
+		},
+		{
+			name: "fork access node without port",
+			modify: func(c *Config) {
+				c.Network = "emulator-fork:testnet"
+				c.Flow.Fork.AccessNode = "localhost"
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid port range",
 			modify: func(c *Config) {
@@ -133,6 +171,251 @@ func TestValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "persist without db path",
+			modify: func(c *Config) {
+				c.Flow.Persist = true
+				c.Flow.DBPath = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "block per transaction",
+			modify: func(c *Config) {
+				c.Flow.BlockTime = 0
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative block time",
+			modify: func(c *Config) {
+				c.Flow.BlockTime = -time.Second
+			},
+			wantErr: true,
+		},
+		{
+			name: "coverage without folder",
+			modify: func(c *Config) {
+				c.Flow.Coverage.Enabled = true
+				c.Flow.Coverage.Folder = " "
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero transaction gas limit",
+			modify: func(c *Config) {
+				c.Flow.Emulator.TransactionMaxGasLimit = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "genesis token supply too large",
+			modify: func(c *Config) {
+				c.Flow.Emulator.GenesisTokenSupply = 1e12
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom evm chain id and gas price",
+			modify: func(c *Config) {
+				c.EVM.ChainID = 31337
+				c.EVM.GasPrice = 1_000_000_000
+			},
+			wantErr: false,
+		},
+		{
+			name: "zero evm chain id",
+			modify: func(c *Config) {
+				c.EVM.ChainID = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid evm tx state validation",
+			modify: func(c *Config) {
+				c.EVM.TxStateValidation = "optimistic"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid evm wallet key",
+			modify: func(c *Config) {
+				c.EVM.WalletKey = "0x1234"
+			},
+			wantErr: true,
+		},
+		{
+			// Only read when the gateway starts, following a network does not need it
+			name: "missing evm coa key file",
+			modify: func(c *Config) {
+				c.EVM.COAKeyFile = "does-not-exist.pkey"
+			},
+			wantErr: false,
+		},
+		{
+			name: "evm accounts from key and mnemonic",
+			modify: func(c *Config) {
+				c.EVM.Accounts = []EVMAccountConfig{
+					{Name: "alice", PrivateKey: "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"},
+					{Name: "bob", Mnemonic: "test test test test test test test test test test test junk", DerivationPath: "m/44'/60'/0'/0/1", Balance: 100},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "evm account without key",
+			modify: func(c *Config) {
+				c.EVM.Accounts = []EVMAccountConfig{{Name: "alice"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate evm account name",
+			modify: func(c *Config) {
+				key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+				c.EVM.Accounts = []EVMAccountConfig{{Name: "alice", PrivateKey: key}, {Name: "alice", PrivateKey: key}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid evm account mnemonic",
+			modify: func(c *Config) {
+				c.EVM.Accounts = []EVMAccountConfig{{Name: "alice", Mnemonic: "not a mnemonic"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "evm contracts deployed by the unfunded wallet with a gas price",
+			modify: func(c *Config) {
+				c.EVM.GasPrice = 1_000_000_000
+				c.EVM.Contracts = []EVMContractConfig{{Name: "token", Artifact: "out/Token.sol/Token.json"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "evm contract with unknown deployer",
+			modify: func(c *Config) {
+				c.EVM.Contracts = []EVMContractConfig{{Name: "token", Artifact: "config_test.go", Deployer: "alice"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "evm contract artifact not built yet",
+			modify: func(c *Config) {
+				c.EVM.Contracts = []EVMContractConfig{{Name: "token", Artifact: "out/Token.sol/Token.json"}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "evm contract named like an evm account",
+			modify: func(c *Config) {
+				c.EVM.Accounts = []EVMAccountConfig{{Name: "alice", PrivateKey: "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"}}
+				c.EVM.Contracts = []EVMContractConfig{{Name: "alice", Artifact: "config_test.go"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "subscription indexer",
+			modify: func(c *Config) {
+				c.Indexer.Backend = IndexerBackendSubscription
+				c.Indexer.SubscriptionHost = "access.mainnet.nodes.onflow.org:9000"
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid indexer backend",
+			modify: func(c *Config) {
+				c.Indexer.Backend = "websocket"
+			},
+			wantErr: true,
+		},
+		{
+			name: "backfill range on mainnet",
+			modify: func(c *Config) {
+				c.Network = "mainnet"
+				c.Indexer.StartHeight = 1000
+				c.Indexer.EndHeight = 1100
+			},
+			wantErr: false,
+		},
+		{
+			name: "start height on the emulator",
+			modify: func(c *Config) {
+				c.Indexer.StartHeight = 1000
+			},
+			wantErr: true,
+		},
+		{
+			name: "end height without start height",
+			modify: func(c *Config) {
+				c.Network = "testnet"
+				c.Indexer.EndHeight = 1100
+			},
+			wantErr: true,
+		},
+		{
+			name: "end height below start height",
+			modify: func(c *Config) {
+				c.Network = "testnet"
+				c.Indexer.StartHeight = 1000
+				c.Indexer.EndHeight = 999
+			},
+			wantErr: true,
+		},
+		{
+			name: "sequential indexer",
+			modify: func(c *Config) {
+				c.Indexer.Concurrency = 1
+			},
+			wantErr: false,
+		},
+		{
+			name: "zero indexer concurrency",
+			modify: func(c *Config) {
+				c.Indexer.Concurrency = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "indexer watchlist",
+			modify: func(c *Config) {
+				c.Indexer.Filter = IndexerFilter{
+					Addresses: []string{"0x1654653399040a61"},
+					Contracts: []string{"A.0b2a3299cc857e29.TopShot"},
+					Events:    []string{"A.1654653399040a61.FlowToken."},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "short watched address",
+			modify: func(c *Config) {
+				c.Indexer.Filter.Addresses = []string{"0x01"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "watched contract without address",
+			modify: func(c *Config) {
+				c.Indexer.Filter.Contracts = []string{"FlowToken"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "persisted history without entries",
+			modify: func(c *Config) {
+				c.UI.History.MaxStored = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "history not persisted",
+			modify: func(c *Config) {
+				c.UI.History.Persist = false
+				c.UI.History.MaxStored = 0
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid log level",
 			modify: func(c *Config) {
@@ -154,6 +437,73 @@ func TestValidation(t *testing.T) {
 	}
 }
 
+func TestParseFork(t *testing.T) {
+	fork, ok, err := ParseFork("emulator-fork:mainnet@123456")
+	if !ok || err != nil {
+		t.Fatalf("expected valid fork, got ok=%v err=%v", ok, err)
+	}
+	if fork.Network != "mainnet" || fork.Height != 123456 {
+		t.Errorf("expected mainnet@123456, got %s@%d", fork.Network, fork.Height)
+	}
+
+	fork, ok, err = ParseFork("emulator-fork:testnet")
+	if !ok || err != nil || fork.Height != 0 {
+		t.Errorf("expected testnet fork at latest height, got %+v ok=%v err=%v", fork, ok, err)
+	}
+
+	if _, ok, _ := ParseFork("emulator"); ok {
+		t.Errorf("expected emulator not to be a fork")
+	}
+
+	if _, _, err := ParseFork("emulator-fork:mainnet@abc"); err == nil {
+		t.Errorf("expected error for invalid height")
+	}
+
+	if !IsEmulator("emulator-fork:mainnet@1") || IsEmulator("mainnet") {
+		t.Errorf("IsEmulator does not match forks and live networks correctly")
+	}
+
+	cfg := DefaultConfig()
+	if cfg.ForkAccessNode("mainnet") != "access.mainnet.nodes.onflow.org:9000" {
+		t.Errorf("expected default mainnet access node, got %s", cfg.ForkAccessNode("mainnet"))
+	}
+	cfg.Flow.Fork.AccessNode = "localhost:3570"
+	if cfg.ForkAccessNode("mainnet") != "localhost:3570" {
+		t.Errorf("expected configured access node, got %s", cfg.ForkAccessNode("mainnet"))
+	}
+}
+
+func TestLoadManualBlockTime(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "aether.yaml")
+	if err := os.WriteFile(configPath, []byte("flow:\n  block_time: manual\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+	if !cfg.Flow.ManualBlocks || cfg.Flow.BlockTime != DefaultConfig().Flow.BlockTime {
+		t.Errorf("expected manual block production with the default block time, got %v and %v", cfg.Flow.ManualBlocks, cfg.Flow.BlockTime)
+	}
+}
+
+func TestLoadZeroBlockTime(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "aether.yaml")
+	if err := os.WriteFile(configPath, []byte("flow:\n  block_time: 0\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+	// 0 still commits a block after every transaction
+	if cfg.Flow.ManualBlocks || cfg.Flow.BlockTime != 0 {
+		t.Errorf("expected a block per transaction, got manual %v and block time %v", cfg.Flow.ManualBlocks, cfg.Flow.BlockTime)
+	}
+}
+
 func TestLogLevelInheritance(t *testing.T) {
 	cfg := &Config{
 		Logging: LoggingConfig{
@@ -186,3 +536,155 @@ func TestLogLevelInheritance(t *testing.T) {
 		t.Errorf("expected evm_gateway to default to 'error', got '%s'", cfg.Logging.Level.EVMGateway)
 	}
 }
+
+func TestLoadPortOffset(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "aether.yaml")
+	if err := os.WriteFile(configPath, []byte("ports:\n  offset: 100\n  dev_wallet: 9701\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+
+	if cfg.Ports.Emulator.GRPC != 3669 {
+		t.Errorf("expected emulator gRPC port 3669, got %d", cfg.Ports.Emulator.GRPC)
+	}
+	if cfg.Ports.DevWallet != 9801 {
+		t.Errorf("expected dev wallet port 9801, got %d", cfg.Ports.DevWallet)
+	}
+	if cfg.Ports.Control != 8802 {
+		t.Errorf("expected control port 8802, got %d", cfg.Ports.Control)
+	}
+}
+
+func TestRuntimeFile(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Ports.Emulator.GRPC = 4569
+	path := filepath.Join(t.TempDir(), ".aether", "runtime.json")
+
+	if err := WriteRuntime(path, NewRuntime(cfg)); err != nil {
+		t.Fatalf("unexpected error writing runtime file: %v", err)
+	}
+
+	rt, err := ReadRuntime(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading runtime file: %v", err)
+	}
+	if rt.Ports["emulator.grpc"] != 4569 {
+		t.Errorf("expected emulator gRPC port 4569, got %d", rt.Ports["emulator.grpc"])
+	}
+	if rt.AccessNode != "127.0.0.1:4569" {
+		t.Errorf("expected access node 127.0.0.1:4569, got %s", rt.AccessNode)
+	}
+	if rt.Ports["control"] != cfg.Ports.Control {
+		t.Errorf("expected control port %d, got %d", cfg.Ports.Control, rt.Ports["control"])
+	}
+
+	other := DefaultConfig()
+	other.ApplyRuntime(rt)
+	if other.Ports.Emulator.GRPC != 4569 {
+		t.Errorf("expected emulator gRPC port 4569 from the runtime file, got %d", other.Ports.Emulator.GRPC)
+	}
+
+	if !rt.Running() {
+		t.Error("expected the runtime file of this process to be running")
+	}
+	// Far above any pid a system hands out
+	if (Runtime{PID: 1 << 30}).Running() || (Runtime{}).Running() {
+		t.Error("expected a runtime file without a running process to not be running")
+	}
+}
+
+func TestRemoveRuntime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	if err := WriteRuntime(path, Runtime{PID: 2}); err != nil {
+		t.Fatalf("unexpected error writing runtime file: %v", err)
+	}
+
+	// Written by another aether since, it is still running
+	if err := RemoveRuntime(path, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the runtime file of another process to be kept, got %v", err)
+	}
+
+	if err := RemoveRuntime(path, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the runtime file to be removed, got %v", err)
+	}
+	if err := RemoveRuntime(path, 2); err != nil {
+		t.Errorf("expected no error for a missing runtime file, got %v", err)
+	}
+}
+
+func TestLoadEVMAccounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "aether.yaml")
+	content := `
+evm:
+  accounts:
+    - name: alice
+      mnemonic: "test test test test test test test test test test test junk"
+      balance: 50
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+
+	if len(cfg.EVM.Accounts) != 1 {
+		t.Fatalf("expected 1 evm account, got %d", len(cfg.EVM.Accounts))
+	}
+	if cfg.EVM.Accounts[0].Name != "alice" || cfg.EVM.Accounts[0].Balance != 50 {
+		t.Errorf("unexpected evm account %+v", cfg.EVM.Accounts[0])
+	}
+}
+
+func TestLoadEVMContracts(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "aether.yaml")
+	artifactPath := filepath.Join(tmpDir, "Token.json")
+	if err := os.WriteFile(artifactPath, []byte(`{"abi":[],"bytecode":"0x6080"}`), 0644); err != nil {
+		t.Fatalf("failed to write artifact: %v", err)
+	}
+	content := `
+evm:
+  accounts:
+    - name: alice
+      mnemonic: "test test test test test test test test test test test junk"
+  contracts:
+    - name: token
+      artifact: ` + artifactPath + `
+      deployer: alice
+      args: ["alice", "1000"]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+
+	if len(cfg.EVM.Contracts) != 1 {
+		t.Fatalf("expected 1 evm contract, got %d", len(cfg.EVM.Contracts))
+	}
+	contract := cfg.EVM.Contracts[0]
+	if contract.Name != "token" || contract.Deployer != "alice" || len(contract.Args) != 2 || contract.Args[0] != "alice" {
+		t.Errorf("unexpected evm contract %+v", contract)
+	}
+	if cfg.EVM.DeploymentsFile != ".aether/evm-contracts.json" {
+		t.Errorf("expected default deployments file, got %s", cfg.EVM.DeploymentsFile)
+	}
+}
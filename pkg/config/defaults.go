@@ -11,12 +11,53 @@ func DefaultConfig() *Config {
 
 			//I have not tweaked this on emulator along with block_time
 			BlockTime:                   1 * time.Second,
+			ManualBlocks:                false, // Only commit blocks on demand, from the dashboard or with aether commit
 			InitTransactionsFolder:      "",    // Empty string means use root aether folder (no subfolder filtering)
 			InitTransactionsInteractive: false, // If true, prompt user to select folder at startup
+
+			//persist if you do not want to wait for deploys and init transactions on every restart
+			Persist:  false,
+			Snapshot: false,
+			DBPath:   "./flowdb",
+
+			//raise the gas limits if you run heavy migrations, turn off storage limits to not have to fund accounts
+			Emulator: EmulatorConfig{
+				TransactionMaxGasLimit:       9999,
+				ScriptGasLimit:               100000,
+				StorageLimitEnabled:          true,
+				TransactionFeesEnabled:       true,
+				SimpleAddressesEnabled:       false, //if you turn this on the addresses in flow.json will not match
+				GenesisTokenSupply:           1000000000.0,
+				TransactionExpiry:            10,
+				ContractRemovalEnabled:       true,
+				SetupEVMEnabled:              true, //the evm gateway needs this, so do not turn it off unless you do not use evm
+				ScheduledTransactionsEnabled: true,
+			},
+			//only used with network emulator-fork:mainnet@<height>, empty means the public access node. point it to a local emulator in tests
+			Fork: ForkConfig{
+				AccessNode: "",
+			},
+			//coverage slows down execution a bit, so it is off unless you want a report
+			Coverage: CoverageConfig{
+				Enabled: false,
+				Folder:  "./coverage",
+			},
+			//redeploying on save is opt in, removing a contract to redeploy it wipes what is stored with it
+			HotReload: HotReloadConfig{
+				Enabled:            false,
+				RemoveIncompatible: false,
+			},
 		},
 		Indexer: IndexerConfig{
 			//I have not tweaked this in indexer along with block_time
 			PollingInterval: 200 * time.Millisecond,
+			//the emulator does not support subscriptions, it is mostly useful when following testnet or mainnet
+			Backend: IndexerBackendPolling,
+			//resume where the last run stopped when following a network, but do not spend ages catching up
+			Checkpoint: true,
+			MaxCatchUp: 1000,
+			//catching up after a backfill or laptop sleep fetches this many blocks at a time
+			Concurrency: 8,
 			//these settings affect how things are INDEXED, so if you tweak these eventual filters might need changing
 			Underflow: UnderflowConfig{
 				ByteArrayAsHex:       true, //i find hex values easier to read, but for absolute correctness set to false
@@ -38,11 +79,24 @@ func DefaultConfig() *Config {
 				Profiler: 6060,
 				Metrics:  9091,
 			},
+			Control: 8702,
 		},
 		//some people might want to put this elsewhere or keep it
 		EVM: EVMConfig{
 			DatabasePath:          "evm-gateway-db",
 			DeleteDatabaseOnStart: true,
+			ChainID:               646, // Flow EVM preview net
+			GasPrice:              0,
+			EnforceGasPrice:       true,
+			TxStateValidation:     "local-index",
+			WalletEnabled:         true,
+			//the standard EVM gateway key of flow-cli
+			WalletKey:              "2619878f0e2ff438d17835c2a4561cb87b4d24d72d12ec34569acd0dd4af7c21",
+			FilterExpiry:           5 * time.Minute,
+			TxRequestLimitDuration: 5 * time.Minute,
+			TxBatchInterval:        1200 * time.Millisecond,
+			EOAActivityCacheTTL:    10 * time.Second,
+			DeploymentsFile:        ".aether/evm-contracts.json",
 		},
 
 		//you never know how people want to log, the evm gateway is very verbose so set it to error
@@ -69,6 +123,8 @@ func DefaultConfig() *Config {
 				MaxTransactions: 10000,
 				MaxEvents:       10000,
 				MaxLogLines:     10000,
+				Persist:         true,
+				MaxStored:       100000,
 			},
 			//if you use a narrower terminal then you could set these
 			Layout: LayoutConfig{
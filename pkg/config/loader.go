@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
@@ -69,6 +70,12 @@ func Load(configPath string, logger zerolog.Logger) (*Config, error) {
 	cfg := DefaultConfig()
 	logger.Debug().Msg("Starting with defaults from defaults.go")
 	
+	// block_time: manual is the same as manual_blocks: true, it is not a valid duration string
+	if strings.EqualFold(strings.TrimSpace(v.GetString("flow.block_time")), ManualBlockTime) {
+		v.Set("flow.manual_blocks", true)
+		v.Set("flow.block_time", cfg.Flow.BlockTime.String())
+	}
+
 	// Unmarshal config file on top of defaults (if file exists)
 	if err := v.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
@@ -90,6 +97,9 @@ func Load(configPath string, logger zerolog.Logger) (*Config, error) {
 	// Apply inheritance for log levels
 	applyLogLevelInheritance(cfg)
 
+	// Move every port by ports.offset before the ports are validated
+	applyPortOffset(cfg)
+
 	// Validate configuration
 	if err := validate(cfg); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
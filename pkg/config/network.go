@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ForkPrefix starts a network name that forks a live network into the local emulator,
+// e.g. emulator-fork:mainnet@123456 or emulator-fork:testnet to fork at the latest sealed block
+const ForkPrefix = "emulator-fork:"
+
+// DefaultForkAccessNodes are the public gRPC access nodes used when flow.fork.access_node is not set
+var DefaultForkAccessNodes = map[string]string{
+	"mainnet": "access.mainnet.nodes.onflow.org:9000",
+	"testnet": "access.devnet.nodes.onflow.org:9000",
+}
+
+// Fork describes a network forked into the local emulator
+type Fork struct {
+	Network string // The forked network, mainnet or testnet
+	Height  uint64 // Height to fork at, 0 means the latest sealed block
+}
+
+// ParseFork parses a network name of the form emulator-fork:<network>[@<height>].
+// ok is false if the network is not a fork.
+func ParseFork(network string) (fork Fork, ok bool, err error) {
+	if !strings.HasPrefix(network, ForkPrefix) {
+		return Fork{}, false, nil
+	}
+
+	spec := strings.TrimPrefix(network, ForkPrefix)
+	name, height, hasHeight := strings.Cut(spec, "@")
+	if _, known := DefaultForkAccessNodes[name]; !known {
+		return Fork{}, true, fmt.Errorf("invalid fork network '%s': must be mainnet or testnet", name)
+	}
+
+	fork = Fork{Network: name}
+	if hasHeight {
+		fork.Height, err = strconv.ParseUint(height, 10, 64)
+		if err != nil || fork.Height == 0 {
+			return Fork{}, true, fmt.Errorf("invalid fork height '%s': must be a positive block height", height)
+		}
+	}
+	return fork, true, nil
+}
+
+// IsEmulator reports whether the network runs the local emulator, either fresh or forked from a live network
+func IsEmulator(network string) bool {
+	return network == "emulator" || strings.HasPrefix(network, ForkPrefix)
+}
+
+// ForkAccessNode returns the access node to fork the given network from
+func (c *Config) ForkAccessNode(network string) string {
+	if c.Flow.Fork.AccessNode != "" {
+		return c.Flow.Fork.AccessNode
+	}
+	return DefaultForkAccessNodes[network]
+}
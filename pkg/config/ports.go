@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RuntimeFile is where a running aether writes the ports it listens on, relative to the project folder.
+// Other tools, like a frontend dev server or aether commit, read it to find the services.
+const RuntimeFile = ".aether/runtime.json"
+
+// NamedPort is one port of PortsConfig
+type NamedPort struct {
+	Key  string // Config key below ports, e.g. emulator.grpc
+	Name string // Readable name, e.g. Emulator gRPC
+	Port *int
+}
+
+// All returns every port a local service listens on
+func (p *PortsConfig) All() []NamedPort {
+	return []NamedPort{
+		{"emulator.grpc", "Emulator gRPC", &p.Emulator.GRPC},
+		{"emulator.rest", "Emulator REST", &p.Emulator.REST},
+		{"emulator.admin", "Emulator Admin", &p.Emulator.Admin},
+		{"emulator.debugger", "Emulator Debugger", &p.Emulator.Debugger},
+		{"dev_wallet", "Dev Wallet", &p.DevWallet},
+		{"evm.rpc", "EVM Gateway RPC", &p.EVM.RPC},
+		{"evm.profiler", "EVM Gateway Profiler", &p.EVM.Profiler},
+		{"evm.metrics", "EVM Gateway Metrics", &p.EVM.Metrics},
+		{"control", "Aether Control API", &p.Control},
+	}
+}
+
+// applyPortOffset adds ports.offset to every port
+func applyPortOffset(cfg *Config) {
+	if cfg.Ports.Offset == 0 {
+		return
+	}
+	for _, p := range cfg.Ports.All() {
+		*p.Port += cfg.Ports.Offset
+	}
+}
+
+// Runtime is the content of RuntimeFile
+type Runtime struct {
+	PID        int            `json:"pid"`
+	Network    string         `json:"network"`
+	Ports      map[string]int `json:"ports"`       // Keyed by the config key, e.g. emulator.grpc
+	AccessNode string         `json:"access_node"` // gRPC host:port of the emulator
+	REST       string         `json:"rest"`
+	DevWallet  string         `json:"dev_wallet"`
+	EVMRPC     string         `json:"evm_rpc,omitempty"`
+}
+
+// NewRuntime describes the ports of the local services in cfg
+func NewRuntime(cfg *Config) Runtime {
+	ports := make(map[string]int)
+	for _, p := range cfg.Ports.All() {
+		ports[p.Key] = *p.Port
+	}
+
+	rt := Runtime{
+		PID:        os.Getpid(),
+		Network:    cfg.Network,
+		Ports:      ports,
+		AccessNode: fmt.Sprintf("127.0.0.1:%d", cfg.Ports.Emulator.GRPC),
+		REST:       fmt.Sprintf("http://127.0.0.1:%d", cfg.Ports.Emulator.REST),
+		DevWallet:  fmt.Sprintf("http://127.0.0.1:%d", cfg.Ports.DevWallet),
+	}
+	if cfg.Flow.Emulator.SetupEVMEnabled {
+		rt.EVMRPC = fmt.Sprintf("http://127.0.0.1:%d", cfg.Ports.EVM.RPC)
+	}
+	return rt
+}
+
+// WriteRuntime writes rt to path, creating the folder if needed
+func WriteRuntime(path string, rt Runtime) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create runtime folder: %w", err)
+	}
+	data, err := json.MarshalIndent(rt, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Readers never see a half written file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write runtime file: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadRuntime reads the runtime file of a running aether
+func ReadRuntime(path string) (Runtime, error) {
+	var rt Runtime
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rt, err
+	}
+	if err := json.Unmarshal(data, &rt); err != nil {
+		return rt, fmt.Errorf("invalid runtime file %s: %w", path, err)
+	}
+	return rt, nil
+}
+
+// RemoveRuntime removes the runtime file at path if it was written by the process with pid. Another aether
+// started in the same folder overwrites the file, it is left alone for that one.
+func RemoveRuntime(path string, pid int) error {
+	rt, err := ReadRuntime(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err == nil && rt.PID != pid {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// Running reports whether the aether that wrote rt is still running. A runtime file is left behind
+// when aether is killed, its ports are then no longer in use by it.
+func (rt Runtime) Running() bool {
+	return processAlive(rt.PID)
+}
+
+// ApplyRuntime uses the ports of a running aether, so commands that talk to it find the services
+// even when ports.auto or ports.offset moved them
+func (c *Config) ApplyRuntime(rt Runtime) {
+	for _, p := range c.Ports.All() {
+		if port, ok := rt.Ports[p.Key]; ok && port != 0 {
+			*p.Port = port
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package config
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether a process with pid is running
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Signal 0 only checks the process, EPERM means it runs as another user
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
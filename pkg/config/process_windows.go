@@ -0,0 +1,19 @@
+//go:build windows
+
+package config
+
+import "os"
+
+// processAlive reports whether a process with pid is running
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// FindProcess opens the process on Windows, which fails once it has exited
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/bjartek/aether/pkg/evm"
 )
 
 // validate validates the configuration
@@ -12,11 +16,29 @@ func validate(cfg *Config) error {
 		return err
 	}
 
+	// Validate flow settings
+	if err := validateFlow(cfg.Flow); err != nil {
+		return err
+	}
+
+	// Validate indexer settings
+	if err := validateIndexer(cfg.Indexer); err != nil {
+		return err
+	}
+	if err := cfg.ValidateIndexRange(); err != nil {
+		return err
+	}
+
 	// Validate ports
 	if err := validatePorts(cfg.Ports); err != nil {
 		return err
 	}
 
+	// Validate EVM gateway settings
+	if err := validateEVM(cfg.EVM); err != nil {
+		return err
+	}
+
 	// Validate log levels
 	if err := validateLogLevels(cfg.Logging.Level); err != nil {
 		return err
@@ -38,8 +60,115 @@ func validateNetwork(network string) error {
 		"mainnet":  true,
 	}
 
+	// A fork runs the local emulator on top of mainnet or testnet state
+	if _, ok, err := ParseFork(network); ok {
+		return err
+	}
+
 	if !validNetworks[network] {
-		return fmt.Errorf("invalid network mode '%s': must be one of: emulator, testnet, mainnet", network)
+		return fmt.Errorf("invalid network mode '%s': must be one of: emulator, testnet, mainnet, emulator-fork:<mainnet|testnet>@<height>", network)
+	}
+
+	return nil
+}
+
+// validateIndexer validates how blocks are streamed
+func validateIndexer(indexer IndexerConfig) error {
+	if indexer.PollingInterval <= 0 {
+		return fmt.Errorf("invalid indexer.polling_interval %s: must be positive", indexer.PollingInterval)
+	}
+	if indexer.Concurrency < 1 || indexer.Concurrency > 64 {
+		return fmt.Errorf("invalid indexer.concurrency %d: must be between 1 and 64", indexer.Concurrency)
+	}
+	if indexer.Backend != IndexerBackendPolling && indexer.Backend != IndexerBackendSubscription {
+		return fmt.Errorf("invalid indexer.backend '%s': must be one of: %s, %s", indexer.Backend, IndexerBackendPolling, IndexerBackendSubscription)
+	}
+	return validateIndexerFilter(indexer.Filter)
+}
+
+// validateIndexerFilter validates the watchlist of the indexer
+func validateIndexerFilter(filter IndexerFilter) error {
+	for _, address := range filter.Addresses {
+		if b, err := hex.DecodeString(strings.TrimPrefix(address, "0x")); err != nil || len(b) != 8 {
+			return fmt.Errorf("invalid address '%s' in indexer.filter.addresses: must be an 8 byte hex Flow address", address)
+		}
+	}
+	for _, contract := range filter.Contracts {
+		parts := strings.Split(contract, ".")
+		if len(parts) != 3 || parts[0] != "A" || parts[2] == "" {
+			return fmt.Errorf("invalid contract '%s' in indexer.filter.contracts: must look like A.<address>.<name>", contract)
+		}
+		if _, err := hex.DecodeString(strings.TrimPrefix(parts[1], "0x")); err != nil {
+			return fmt.Errorf("invalid contract '%s' in indexer.filter.contracts: %w", contract, err)
+		}
+	}
+	for _, event := range filter.Events {
+		if strings.TrimSpace(event) == "" {
+			return fmt.Errorf("indexer.filter.events must not contain empty prefixes")
+		}
+	}
+	return nil
+}
+
+// ValidateIndexRange validates indexer.start_height and indexer.end_height, which can also be set by flags
+func (c *Config) ValidateIndexRange() error {
+	start, end := c.Indexer.StartHeight, c.Indexer.EndHeight
+	if start == 0 && end == 0 {
+		return nil
+	}
+	if IsEmulator(c.Network) {
+		return fmt.Errorf("indexer.start_height and indexer.end_height can only be used when following testnet or mainnet")
+	}
+	if start == 0 {
+		return fmt.Errorf("indexer.end_height needs indexer.start_height")
+	}
+	// The stream starts after the height it is given and 0 means the latest block
+	if start < 2 {
+		return fmt.Errorf("invalid indexer.start_height %d: must be 2 or higher", start)
+	}
+	if end != 0 && end < start {
+		return fmt.Errorf("invalid indexer.end_height %d: must not be below indexer.start_height %d", end, start)
+	}
+	return nil
+}
+
+// validateFlow validates the emulator persistence settings
+func validateFlow(flow FlowConfig) error {
+	if flow.BlockTime < 0 {
+		return fmt.Errorf("invalid block_time %s: must be 0 (a block per transaction) or positive", flow.BlockTime)
+	}
+
+	if flow.Persist && strings.TrimSpace(flow.DBPath) == "" {
+		return fmt.Errorf("db_path must be set when persist is enabled")
+	}
+
+	if flow.Fork.AccessNode != "" && !strings.Contains(flow.Fork.AccessNode, ":") {
+		return fmt.Errorf("invalid fork.access_node '%s': must include a port, e.g. localhost:3570", flow.Fork.AccessNode)
+	}
+
+	if flow.Coverage.Enabled && strings.TrimSpace(flow.Coverage.Folder) == "" {
+		return fmt.Errorf("coverage.folder must be set when coverage is enabled")
+	}
+
+	return validateEmulator(flow.Emulator)
+}
+
+// maxUFix64 is the largest value a Cadence UFix64 can hold
+const maxUFix64 = 184467440737.09551615
+
+// validateEmulator validates the emulator tuning options
+func validateEmulator(emu EmulatorConfig) error {
+	if emu.TransactionMaxGasLimit < 1 {
+		return fmt.Errorf("flow.emulator.transaction_max_gas_limit must be at least 1")
+	}
+	if emu.ScriptGasLimit < 1 {
+		return fmt.Errorf("flow.emulator.script_gas_limit must be at least 1")
+	}
+	if emu.TransactionExpiry < 1 {
+		return fmt.Errorf("flow.emulator.transaction_expiry must be at least 1 block")
+	}
+	if emu.GenesisTokenSupply <= 0 || emu.GenesisTokenSupply > maxUFix64 {
+		return fmt.Errorf("invalid flow.emulator.genesis_token_supply %f: must be a positive UFix64 value", emu.GenesisTokenSupply)
 	}
 
 	return nil
@@ -88,10 +217,119 @@ func validatePorts(ports PortsConfig) error {
 	if err := checkPort(ports.EVM.Metrics, "evm.metrics"); err != nil {
 		return err
 	}
+	if err := checkPort(ports.Control, "control"); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// validateEVM validates the EVM gateway settings
+func validateEVM(evm EVMConfig) error {
+	if evm.ChainID == 0 {
+		return fmt.Errorf("evm.chain_id must be set")
+	}
+
+	if evm.TxStateValidation != "local-index" && evm.TxStateValidation != "tx-seal" {
+		return fmt.Errorf("invalid evm.tx_state_validation '%s': must be one of: local-index, tx-seal", evm.TxStateValidation)
+	}
+
+	// The address of the wallet key is the coinbase, so it is needed even without the wallet API
+	if b, err := hex.DecodeString(strings.TrimPrefix(evm.WalletKey, "0x")); err != nil || len(b) != 32 {
+		return fmt.Errorf("invalid evm.wallet_key: must be a 32 byte hex private key")
+	}
+
+	durations := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"filter_expiry", evm.FilterExpiry},
+		{"tx_request_limit_duration", evm.TxRequestLimitDuration},
+		{"tx_batch_interval", evm.TxBatchInterval},
+		{"eoa_activity_cache_ttl", evm.EOAActivityCacheTTL},
+	}
+	for _, d := range durations {
+		if d.d <= 0 {
+			return fmt.Errorf("invalid evm.%s %s: must be positive", d.name, d.d)
+		}
+	}
+
+	if err := validateEVMAccounts(evm.Accounts); err != nil {
+		return err
+	}
+	return validateEVMContracts(evm)
+}
+
+// validateEVMAccounts validates the named EVM accounts, every account needs a unique name and a key
+func validateEVMAccounts(accounts []EVMAccountConfig) error {
+	names := make(map[string]bool, len(accounts))
+	for i, account := range accounts {
+		if strings.TrimSpace(account.Name) == "" {
+			return fmt.Errorf("evm.accounts[%d]: name must be set", i)
+		}
+		if names[account.Name] {
+			return fmt.Errorf("evm.accounts: duplicate name '%s'", account.Name)
+		}
+		names[account.Name] = true
+
+		switch {
+		case account.PrivateKey != "" && account.Mnemonic != "":
+			return fmt.Errorf("evm account '%s': set either private_key or mnemonic, not both", account.Name)
+		case account.PrivateKey != "":
+			if _, err := evm.ParsePrivateKey(account.PrivateKey); err != nil {
+				return fmt.Errorf("evm account '%s': %w", account.Name, err)
+			}
+		case account.Mnemonic != "":
+			if _, err := evm.DeriveKey(account.Mnemonic, account.DerivationPath); err != nil {
+				return fmt.Errorf("evm account '%s': %w", account.Name, err)
+			}
+		default:
+			return fmt.Errorf("evm account '%s': private_key or mnemonic must be set", account.Name)
+		}
+
+		if account.Balance < 0 || account.Balance > maxUFix64 {
+			return fmt.Errorf("invalid balance %f for evm account '%s': must be a UFix64 value", account.Balance, account.Name)
+		}
+	}
+	return nil
+}
+
+// validateEVMContracts validates the contracts deployed into the EVM, names are shared with the evm accounts
+func validateEVMContracts(evm EVMConfig) error {
+	if len(evm.Contracts) > 0 && strings.TrimSpace(evm.DeploymentsFile) == "" {
+		return fmt.Errorf("evm.deployments_file must be set when evm.contracts are configured")
+	}
+	// Without evm.accounts the gateway wallet deploys, it has no funds to pay the gas with
+	if len(evm.Contracts) > 0 && len(evm.Accounts) == 0 && evm.GasPrice > 0 {
+		return fmt.Errorf("evm.contracts need a funded deployer when evm.gas_price is set, add one to evm.accounts")
+	}
+
+	accounts := make(map[string]bool, len(evm.Accounts))
+	for _, account := range evm.Accounts {
+		accounts[account.Name] = true
+	}
+
+	names := make(map[string]bool, len(evm.Contracts))
+	for i, contract := range evm.Contracts {
+		if strings.TrimSpace(contract.Name) == "" {
+			return fmt.Errorf("evm.contracts[%d]: name must be set", i)
+		}
+		if names[contract.Name] || accounts[contract.Name] {
+			return fmt.Errorf("evm.contracts: duplicate name '%s'", contract.Name)
+		}
+		names[contract.Name] = true
+
+		// The artifact is read when the contract is deployed, it may not be built yet when following a network
+		if contract.Artifact == "" {
+			return fmt.Errorf("evm contract '%s': artifact must be set", contract.Name)
+		}
+		if contract.Deployer != "" && !accounts[contract.Deployer] {
+			return fmt.Errorf("evm contract '%s': deployer '%s' is not in evm.accounts", contract.Name, contract.Deployer)
+		}
+	}
+	return nil
+}
+
 // validateLogLevels validates log level settings
 func validateLogLevels(levels LogLevelConfig) error {
 	validLevels := map[string]bool{
@@ -153,6 +391,9 @@ func validateUI(ui UIConfig) error {
 	if ui.History.MaxEvents < 1 {
 		return fmt.Errorf("max_events must be at least 1")
 	}
+	if ui.History.Persist && ui.History.MaxStored < 1 {
+		return fmt.Errorf("max_stored must be at least 1 when persist is enabled")
+	}
 
 	return nil
 }
@@ -0,0 +1,19 @@
+package events
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Sink receives the messages produced by the background services (indexer,
+// init transactions, log writer, frontend manager).
+// A *tea.Program satisfies this interface, so the TUI is just one consumer;
+// headless mode plugs in a sink that serializes the messages instead.
+type Sink interface {
+	Send(msg tea.Msg)
+}
+
+// SinkFunc adapts an ordinary function to the Sink interface
+type SinkFunc func(msg tea.Msg)
+
+// Send calls f(msg)
+func (f SinkFunc) Send(msg tea.Msg) {
+	f(msg)
+}
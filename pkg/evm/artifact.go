@@ -0,0 +1,172 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Artifact is a compiled Solidity contract, read from a Foundry (out/) or Hardhat (artifacts/) JSON file
+type Artifact struct {
+	ABI      abi.ABI
+	Bytecode []byte
+}
+
+// artifactFile covers both formats, Foundry has the bytecode in bytecode.object and Hardhat in bytecode
+type artifactFile struct {
+	ABI      json.RawMessage `json:"abi"`
+	Bytecode json.RawMessage `json:"bytecode"`
+}
+
+// LoadArtifact reads a Foundry or Hardhat artifact
+func LoadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	var file artifactFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid artifact %s: %w", path, err)
+	}
+	if len(file.ABI) == 0 || len(file.Bytecode) == 0 {
+		return nil, fmt.Errorf("invalid artifact %s: abi and bytecode are required", path)
+	}
+
+	contractABI, err := abi.JSON(bytes.NewReader(file.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi in %s: %w", path, err)
+	}
+
+	code, err := artifactBytecode(file.Bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode in %s: %w", path, err)
+	}
+	return &Artifact{ABI: contractABI, Bytecode: code}, nil
+}
+
+func artifactBytecode(raw json.RawMessage) ([]byte, error) {
+	var code string
+	if err := json.Unmarshal(raw, &code); err != nil {
+		var foundry struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw, &foundry); err != nil {
+			return nil, err
+		}
+		code = foundry.Object
+	}
+
+	// Placeholders like __$abc$__ are left where a library address has to be linked in
+	if strings.Contains(code, "__") {
+		return nil, fmt.Errorf("contract has unlinked libraries")
+	}
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	b, err := hexutil.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("bytecode is empty, is the contract abstract or an interface?")
+	}
+	return b, nil
+}
+
+// DeployData returns the bytecode followed by the encoded constructor arguments. Address arguments
+// can be a name, resolve turns it into an address.
+func (a *Artifact) DeployData(args []string, resolve func(name string) (common.Address, bool)) ([]byte, error) {
+	inputs := a.ABI.Constructor.Inputs
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("constructor takes %d arguments, got %d", len(inputs), len(args))
+	}
+
+	values := make([]any, 0, len(args))
+	for i, input := range inputs {
+		v, err := parseArg(input.Type, args[i], resolve)
+		if err != nil {
+			return nil, fmt.Errorf("constructor argument %s: %w", input.Name, err)
+		}
+		values = append(values, v)
+	}
+
+	encoded, err := a.ABI.Pack("", values...)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, a.Bytecode...), encoded...), nil
+}
+
+// parseArg converts a constructor argument from the config to the Go type the ABI encoder expects
+func parseArg(t abi.Type, value string, resolve func(string) (common.Address, bool)) (any, error) {
+	value = strings.TrimSpace(value)
+	switch t.T {
+	case abi.AddressTy:
+		if common.IsHexAddress(value) {
+			return common.HexToAddress(value), nil
+		}
+		if resolve != nil {
+			if address, ok := resolve(value); ok {
+				return address, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not an address or a known name", value)
+
+	case abi.BoolTy:
+		return strconv.ParseBool(value)
+
+	case abi.StringTy:
+		return value, nil
+
+	case abi.BytesTy:
+		return hexutil.Decode(value)
+
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > t.Size {
+			return nil, fmt.Errorf("%s is longer than %d bytes", value, t.Size)
+		}
+		arr := reflect.New(t.GetType()).Elem()
+		reflect.Copy(arr, reflect.ValueOf(b))
+		return arr.Interface(), nil
+
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return nil, fmt.Errorf("%s is negative", value)
+		}
+		bits := t.Size
+		if t.T == abi.IntTy {
+			bits-- // The sign bit
+		}
+		if n.BitLen() > bits {
+			return nil, fmt.Errorf("%s does not fit in %s", value, t.String())
+		}
+		// Up to 64 bits the encoder wants the exact Go type, e.g. uint8
+		goType := t.GetType()
+		if goType == reflect.TypeOf(&big.Int{}) {
+			return n, nil
+		}
+		if t.T == abi.IntTy {
+			return reflect.ValueOf(n.Int64()).Convert(goType).Interface(), nil
+		}
+		return reflect.ValueOf(n.Uint64()).Convert(goType).Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t.String())
+}
@@ -0,0 +1,100 @@
+package evm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testABI = `[{"type":"constructor","inputs":[
+	{"name":"owner","type":"address"},
+	{"name":"supply","type":"uint256"},
+	{"name":"name","type":"string"},
+	{"name":"decimals","type":"uint8"},
+	{"name":"salt","type":"bytes32"},
+	{"name":"paused","type":"bool"}]}]`
+
+func writeArtifact(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Token.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write artifact: %v", err)
+	}
+	return path
+}
+
+func TestLoadArtifact(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"foundry", `{"abi":` + testABI + `,"bytecode":{"object":"0x6080"}}`},
+		{"hardhat", `{"abi":` + testABI + `,"bytecode":"0x6080"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, err := LoadArtifact(writeArtifact(t, tt.content))
+			if err != nil {
+				t.Fatalf("LoadArtifact() error = %v", err)
+			}
+			if len(artifact.Bytecode) != 2 {
+				t.Errorf("expected 2 bytes of bytecode, got %d", len(artifact.Bytecode))
+			}
+			if len(artifact.ABI.Constructor.Inputs) != 6 {
+				t.Errorf("expected 6 constructor inputs, got %d", len(artifact.ABI.Constructor.Inputs))
+			}
+		})
+	}
+}
+
+func TestLoadArtifactErrors(t *testing.T) {
+	tests := map[string]string{
+		"no bytecode":        `{"abi":[]}`,
+		"unlinked libraries": `{"abi":[],"bytecode":"0x6080__$1234$__"}`,
+		"empty bytecode":     `{"abi":[],"bytecode":"0x"}`,
+	}
+	for name, content := range tests {
+		if _, err := LoadArtifact(writeArtifact(t, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDeployData(t *testing.T) {
+	artifact, err := LoadArtifact(writeArtifact(t, `{"abi":`+testABI+`,"bytecode":"0x6080"}`))
+	if err != nil {
+		t.Fatalf("LoadArtifact() error = %v", err)
+	}
+
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	resolve := func(name string) (common.Address, bool) {
+		return alice, name == "alice"
+	}
+
+	data, err := artifact.DeployData([]string{"alice", "1000000000000000000000", "Token", "18", "0x01", "false"}, resolve)
+	if err != nil {
+		t.Fatalf("DeployData() error = %v", err)
+	}
+	// bytecode + 6 head words + string length and content
+	if len(data) != 2+8*32 {
+		t.Errorf("expected %d bytes, got %d", 2+8*32, len(data))
+	}
+	if common.BytesToAddress(data[2:34]) != alice {
+		t.Errorf("expected owner %s, got %s", alice, common.BytesToAddress(data[2:34]))
+	}
+
+	invalid := [][]string{
+		{"bob", "1", "Token", "18", "0x01", "false"},
+		{"alice", "-1", "Token", "18", "0x01", "false"},
+		{"alice", "1", "Token", "256", "0x01", "false"},
+		{"alice", "1", "Token"},
+	}
+	for _, args := range invalid {
+		if _, err := artifact.DeployData(args, resolve); err == nil {
+			t.Errorf("DeployData(%v): expected error", args)
+		}
+	}
+}
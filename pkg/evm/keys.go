@@ -0,0 +1,113 @@
+// Package evm contains helpers for the EVM side of the local emulator
+package evm
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	gethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDerivationPath is the first account of the Ethereum BIP-44 path, as used by MetaMask, Hardhat and Foundry
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// hardened is added to the index of a hardened path element, written as 44'
+const hardened uint32 = 0x80000000
+
+// ParsePrivateKey parses a hex secp256k1 private key, with or without 0x prefix
+func ParsePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
+	key, err := gethCrypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return key, nil
+}
+
+// DeriveKey derives the private key at path from a BIP-39 mnemonic, an empty path is DefaultDerivationPath
+func DeriveKey(mnemonic, path string) (*ecdsa.PrivateKey, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := bip39.NewSeed(mnemonic, "")
+	key, chainCode := hmacSHA512([]byte("Bitcoin seed"), seed)
+	for _, index := range indexes {
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return gethCrypto.ToECDSA(key)
+}
+
+// ParseDerivationPath parses a BIP-32 path like m/44'/60'/0'/0/0
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m/", path)
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(part, "'") {
+			offset = hardened
+			part = strings.TrimSuffix(part, "'")
+		}
+		n, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: %w", path, err)
+		}
+		indexes = append(indexes, uint32(n)+offset)
+	}
+	return indexes, nil
+}
+
+// deriveChild derives the private child key at index following BIP-32
+func deriveChild(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	var data []byte
+	if index >= hardened {
+		data = append([]byte{0}, key...)
+	} else {
+		parent, err := gethCrypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = gethCrypto.CompressPubkey(&parent.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	il, ir := hmacSHA512(chainCode, data)
+	n := gethCrypto.S256().Params().N
+	child := new(big.Int).SetBytes(il)
+	if child.Cmp(n) >= 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	child.Add(child, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	return child.FillBytes(make([]byte, 32)), ir, nil
+}
+
+func hmacSHA512(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
@@ -0,0 +1,56 @@
+package evm
+
+import (
+	"testing"
+
+	gethCrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// The default Hardhat and Foundry test mnemonic and its first accounts
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveKey(t *testing.T) {
+	tests := []struct {
+		path    string
+		address string
+	}{
+		{"", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{"m/44'/60'/0'/0/1", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+	}
+
+	for _, tt := range tests {
+		key, err := DeriveKey(testMnemonic, tt.path)
+		if err != nil {
+			t.Fatalf("DeriveKey(%q) error = %v", tt.path, err)
+		}
+		if got := gethCrypto.PubkeyToAddress(key.PublicKey).Hex(); got != tt.address {
+			t.Errorf("DeriveKey(%q) address = %s, want %s", tt.path, got, tt.address)
+		}
+	}
+}
+
+func TestDeriveKeyErrors(t *testing.T) {
+	if _, err := DeriveKey("not a mnemonic", ""); err == nil {
+		t.Error("expected error for invalid mnemonic")
+	}
+	if _, err := DeriveKey(testMnemonic, "44'/60'/0'/0/0"); err == nil {
+		t.Error("expected error for path without m/")
+	}
+	if _, err := DeriveKey(testMnemonic, "m/44'/x"); err == nil {
+		t.Error("expected error for invalid path element")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := ParsePrivateKey("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	if got := gethCrypto.PubkeyToAddress(key.PublicKey).Hex(); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("address = %s", got)
+	}
+
+	if _, err := ParsePrivateKey("1234"); err == nil {
+		t.Error("expected error for short key")
+	}
+}
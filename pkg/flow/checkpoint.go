@@ -0,0 +1,66 @@
+package flow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint is the last block the indexer fully processed on a network, the next start resumes after it
+type Checkpoint struct {
+	Network string    `json:"network"`
+	Height  uint64    `json:"height"`
+	Updated time.Time `json:"updated"`
+}
+
+// CheckpointFile returns where the checkpoint of network is kept, relative to the project folder
+func CheckpointFile(network string) string {
+	return filepath.Join(".aether", "checkpoint-"+network+".json")
+}
+
+// ReadCheckpoint reads a checkpoint written by WriteCheckpoint
+func ReadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// WriteCheckpoint writes cp to path, creating the folder if needed
+func WriteCheckpoint(path string, cp Checkpoint) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint folder: %w", err)
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// A crash while writing leaves the previous checkpoint in place
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ResumeHeight returns the height to stream after when resuming from checkpoint with latest as the
+// latest block. With maxCatchUp above 0 at most that many blocks are caught up on and skipped tells
+// how many were left out. ok is false when the checkpoint can not be used, e.g. after a spork.
+func ResumeHeight(checkpoint, latest, maxCatchUp uint64) (height uint64, skipped uint64, ok bool) {
+	if checkpoint == 0 || checkpoint > latest {
+		return 0, 0, false
+	}
+	if maxCatchUp > 0 && latest-checkpoint > maxCatchUp {
+		height = latest - maxCatchUp
+		return height, height - checkpoint, true
+	}
+	return checkpoint, 0, true
+}
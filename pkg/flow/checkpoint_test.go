@@ -0,0 +1,49 @@
+package flow
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckpointFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CheckpointFile("mainnet"))
+	want := Checkpoint{Network: "mainnet", Height: 1234, Updated: time.Unix(1700000000, 0).UTC()}
+	if err := WriteCheckpoint(path, want); err != nil {
+		t.Fatalf("WriteCheckpoint() error = %v", err)
+	}
+
+	got, err := ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("ReadCheckpoint() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResumeHeight(t *testing.T) {
+	tests := []struct {
+		name                 string
+		checkpoint, latest   uint64
+		maxCatchUp           uint64
+		wantHeight, wantSkip uint64
+		wantOK               bool
+	}{
+		{"within window", 900, 1000, 500, 900, 0, true},
+		{"beyond window", 100, 1000, 500, 500, 400, true},
+		{"no limit", 100, 1000, 0, 100, 0, true},
+		{"at latest", 1000, 1000, 500, 1000, 0, true},
+		{"above latest after a spork", 2000, 1000, 500, 0, 0, false},
+		{"no checkpoint", 0, 1000, 500, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			height, skipped, ok := ResumeHeight(tt.checkpoint, tt.latest, tt.maxCatchUp)
+			if height != tt.wantHeight || skipped != tt.wantSkip || ok != tt.wantOK {
+				t.Errorf("ResumeHeight() = %d, %d, %v, want %d, %d, %v", height, skipped, ok, tt.wantHeight, tt.wantSkip, tt.wantOK)
+			}
+		})
+	}
+}
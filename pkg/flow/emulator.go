@@ -3,15 +3,20 @@ package flow
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
+	"unsafe"
 
 	aetherConfig "github.com/bjartek/aether/pkg/config"
 	"github.com/onflow/cadence"
 	devWallet "github.com/onflow/fcl-dev-wallet/go/wallet"
+	"github.com/onflow/flow-go-sdk/crypto"
 	"github.com/onflow/flow-go/fvm"
 	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-emulator/emulator"
 	"github.com/onflow/flow-emulator/server"
 	"github.com/onflow/flowkit/v2"
 	"github.com/onflow/flowkit/v2/config"
@@ -41,6 +46,18 @@ func InitEmulator(logger *zerolog.Logger, cfg *aetherConfig.Config) (*server.Emu
 
 	pk := *privateKey
 
+	emuCfg := cfg.Flow.Emulator
+	genesisTokenSupply, err := cadence.NewUFix64(fmt.Sprintf("%.8f", emuCfg.GenesisTokenSupply))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid genesis token supply: %w", err)
+	}
+
+	// Without a block ticker the emulator commits a block per transaction, until manual blocks disable that
+	blockTime := cfg.Flow.BlockTime
+	if cfg.Flow.ManualBlocks {
+		blockTime = 0
+	}
+
 	serverConf := &server.Config{
 		GRPCPort:                     cfg.Ports.Emulator.GRPC,
 		GRPCDebug:                    false,
@@ -49,52 +66,123 @@ func InitEmulator(logger *zerolog.Logger, cfg *aetherConfig.Config) (*server.Emu
 		RESTPort:                     cfg.Ports.Emulator.REST,
 		RESTDebug:                    false,
 		HTTPHeaders:                  nil,
-		BlockTime:                    cfg.Flow.BlockTime,
+		BlockTime:                    blockTime,
 		ServicePublicKey:             pk.PublicKey(),
 		ServicePrivateKey:            pk,
 		ServiceKeySigAlgo:            serviceAccount.Key.SigAlgo(),
 		ServiceKeyHashAlgo:           serviceAccount.Key.HashAlgo(),
-		Persist:                      false,
-		Snapshot:                     false,
-		DBPath:                       "./flowdb",
-		GenesisTokenSupply:           cadence.UFix64(1000000000.0),
-		TransactionMaxGasLimit:       9999,
-		ScriptGasLimit:               100000,
-		TransactionExpiry:            10,
-		StorageLimitEnabled:          true,
+		Persist:                      cfg.Flow.Persist,
+		Snapshot:                     cfg.Flow.Snapshot,
+		DBPath:                       cfg.Flow.DBPath,
+		GenesisTokenSupply:           genesisTokenSupply,
+		TransactionMaxGasLimit:       emuCfg.TransactionMaxGasLimit,
+		ScriptGasLimit:               emuCfg.ScriptGasLimit,
+		TransactionExpiry:            emuCfg.TransactionExpiry,
+		StorageLimitEnabled:          emuCfg.StorageLimitEnabled,
 		StorageMBPerFLOW:             fvm.DefaultStorageMBPerFLOW,
 		MinimumStorageReservation:    fvm.DefaultMinimumStorageReservation,
-		TransactionFeesEnabled:       true,
+		TransactionFeesEnabled:       emuCfg.TransactionFeesEnabled,
 		WithContracts:                true,
 		SkipTransactionValidation:    false,
-		SimpleAddressesEnabled:       false,
+		SimpleAddressesEnabled:       emuCfg.SimpleAddressesEnabled,
 		Host:                         "",
 		ChainID:                      flowgo.Emulator,
 		RedisURL:                     "",
-		ContractRemovalEnabled:       true,
+		ContractRemovalEnabled:       emuCfg.ContractRemovalEnabled,
 		SqliteURL:                    "",
-		CoverageReportingEnabled:     false,
+		CoverageReportingEnabled:     cfg.Flow.Coverage.Enabled,
 		CheckpointPath:               "",
 		StateHash:                    "",
 		ComputationReportingEnabled:  true,
-		SetupEVMEnabled:              true,
+		SetupEVMEnabled:              emuCfg.SetupEVMEnabled,
 		SetupVMBridgeEnabled:         true,
-		ScheduledTransactionsEnabled: true,
+		ScheduledTransactionsEnabled: emuCfg.ScheduledTransactionsEnabled,
+	}
+
+	// Fork mode fetches registers lazily from an access node of the forked network
+	fork, isFork, err := aetherConfig.ParseFork(cfg.Network)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isFork {
+		serverConf.ForkHost = cfg.ForkAccessNode(fork.Network)
+		serverConf.ForkHeight = fork.Height
+		// The chain ID is detected from the access node
+		serverConf.ChainID = ""
+		// Local keys cannot sign for real accounts, so signatures are not checked on a fork
+		serverConf.SkipTransactionValidation = true
+		logger.Info().Str("network", fork.Network).Uint64("height", fork.Height).Str("accessNode", serverConf.ForkHost).Msg("Forking network into the emulator")
 	}
 
 	emu := server.NewEmulatorServer(logger, serverConf)
+	if emu == nil {
+		return nil, nil, errors.New("failed to create emulator, see the emulator log for details")
+	}
+
+	dw, err := newDevWallet(cfg, emu, serviceAccount.Address.String(), pk, isFork)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return emu, dw, nil
+}
+
+// InitDevWallet creates a new dev wallet for a running emulator, e.g. to restart it
+func InitDevWallet(cfg *aetherConfig.Config, emu *server.EmulatorServer) (*devWallet.Server, error) {
+	loader := &afero.Afero{Fs: afero.NewOsFs()}
+	state, err := flowkit.Load(config.DefaultPaths(), loader)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccount, err := state.EmulatorServiceAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := serviceAccount.Key.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	_, isFork, err := aetherConfig.ParseFork(cfg.Network)
+	if err != nil {
+		return nil, err
+	}
+	return newDevWallet(cfg, emu, serviceAccount.Address.String(), *privateKey, isFork)
+}
+
+// newDevWallet creates the dev wallet that signs with the emulator service account
+func newDevWallet(cfg *aetherConfig.Config, emu *server.EmulatorServer, serviceAddress string, pk crypto.PrivateKey, isFork bool) (*devWallet.Server, error) {
+	if isFork {
+		// The service account of the forked chain is used instead of the one in flow.json
+		serviceAddress = emu.Emulator().ServiceKey().Address.String()
+	}
 
 	devWalletConfig := &devWallet.FlowConfig{
-		Address:    fmt.Sprintf("0x%s", serviceAccount.Address.String()),
+		Address:    fmt.Sprintf("0x%s", serviceAddress),
 		PrivateKey: strings.TrimPrefix(pk.String(), "0x"),
 		PublicKey:  strings.TrimPrefix(pk.PublicKey().String(), "0x"),
 		AccessNode: fmt.Sprintf("http://localhost:%d", cfg.Ports.Emulator.REST),
 	}
 
-	dw, err := devWallet.NewHTTPServer(uint(cfg.Ports.DevWallet), devWalletConfig)
-	if err != nil {
-		return nil, nil, err
-	}
+	return devWallet.NewHTTPServer(uint(cfg.Ports.DevWallet), devWalletConfig)
+}
 
-	return emu, dw, nil
+// EmulatorLock returns the mutex the emulator holds while it executes transactions and scripts. The coverage
+// and computation reports are written during execution without a lock of their own, so they can only be read
+// safely while holding it.
+//
+// The emulator neither exports the mutex nor has a way to copy the reports, so it is read from the unexported
+// mu field of emulator.Blockchain as of flow-emulator v1.12.2. TestEmulatorLock fails when an upgrade changes it.
+func EmulatorLock(e emulator.Emulator) (sync.Locker, error) {
+	b, ok := e.(*emulator.Blockchain)
+	if !ok || b == nil {
+		return nil, fmt.Errorf("emulator %T can not be paused", e)
+	}
+	field := reflect.ValueOf(b).Elem().FieldByName("mu")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(sync.RWMutex{}) {
+		return nil, fmt.Errorf("emulator.Blockchain has no mu sync.RWMutex field, the emulator can not be paused")
+	}
+	return (*sync.RWMutex)(unsafe.Pointer(field.UnsafeAddr())), nil
 }
@@ -0,0 +1,21 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-emulator/emulator"
+)
+
+func TestEmulatorLock(t *testing.T) {
+	// Fails when an emulator upgrade renames the mutex, the reports could not be read safely anymore
+	lock, err := EmulatorLock(&emulator.Blockchain{})
+	if err != nil {
+		t.Fatalf("EmulatorLock() error = %v", err)
+	}
+	lock.Lock()
+	lock.Unlock()
+
+	if _, err := EmulatorLock(nil); err == nil {
+		t.Error("EmulatorLock(nil) should fail")
+	}
+}
@@ -0,0 +1,131 @@
+package flow
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+
+	aetherConfig "github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/evm"
+	"github.com/bjartek/overflow/v2"
+	"github.com/ethereum/go-ethereum/common"
+	gethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/onflow/flow-go/fvm/systemcontracts"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// EVMAccount is a named EVM externally owned account from evm.accounts
+type EVMAccount struct {
+	Name    string
+	Address common.Address
+	Balance float64 // FLOW the account is funded with
+	Key     *ecdsa.PrivateKey
+}
+
+// ResolveEVMAccounts derives the address of every account in evm.accounts
+func ResolveEVMAccounts(cfg *aetherConfig.Config) ([]EVMAccount, error) {
+	accounts := make([]EVMAccount, 0, len(cfg.EVM.Accounts))
+	for _, account := range cfg.EVM.Accounts {
+		key, err := evmAccountKey(account)
+		if err != nil {
+			return nil, fmt.Errorf("evm account '%s': %w", account.Name, err)
+		}
+
+		balance := account.Balance
+		if balance == 0 {
+			balance = cfg.Flow.NewUserBalance
+		}
+		accounts = append(accounts, EVMAccount{
+			Name:    account.Name,
+			Address: gethCrypto.PubkeyToAddress(key.PublicKey),
+			Balance: balance,
+			Key:     key,
+		})
+	}
+	return accounts, nil
+}
+
+func evmAccountKey(account aetherConfig.EVMAccountConfig) (*ecdsa.PrivateKey, error) {
+	if account.Mnemonic != "" {
+		return evm.DeriveKey(account.Mnemonic, account.DerivationPath)
+	}
+	return evm.ParsePrivateKey(account.PrivateKey)
+}
+
+// FundEVMAccount sends the balance of account from the service account to its EVM address
+func FundEVMAccount(o *overflow.OverflowState, cfg *aetherConfig.Config, account EVMAccount) error {
+	contracts := systemcontracts.SystemContractsForChain(chainID(cfg))
+	res := o.Tx(fmt.Sprintf(`
+import EVM from 0x%s
+import FungibleToken from 0x%s
+import FlowToken from 0x%s
+
+transaction(address: String, amount: UFix64) {
+  prepare(signer: auth(Storage) &Account) {
+    let vault = signer.storage.borrow<auth(FungibleToken.Withdraw) &FlowToken.Vault>(from: /storage/flowTokenVault)
+      ?? panic("Could not borrow the FlowToken vault of the service account")
+    let evmAddress = EVM.addressFromString(address)
+    evmAddress.deposit(from: <- (vault.withdraw(amount: amount) as! @FlowToken.Vault))
+  }
+}`, contracts.EVMContract.Address.Hex(), contracts.FungibleToken.Address.Hex(), contracts.FlowToken.Address.Hex()),
+		overflow.WithSignerServiceAccount(),
+		overflow.WithArg("address", strings.ToLower(account.Address.Hex())),
+		overflow.WithArg("amount", account.Balance),
+	)
+	return res.Err
+}
+
+// ResolveCOAs returns the EVM address of the Cadence-Owned Account stored at /storage/evm for each of
+// the Flow addresses that has one, keyed by Flow address
+func ResolveCOAs(o *overflow.OverflowState, cfg *aetherConfig.Config, addresses []string) (map[string]string, error) {
+	coas := make(map[string]string)
+	if len(addresses) == 0 {
+		return coas, nil
+	}
+
+	contracts := systemcontracts.SystemContractsForChain(chainID(cfg))
+	res := o.Script(fmt.Sprintf(`
+import EVM from 0x%s
+
+access(all) fun main(addresses: [String]): {String: String} {
+  let coas: {String: String} = {}
+  for address in addresses {
+    let account = getAuthAccount<auth(BorrowValue) &Account>(Address.fromString(address)!)
+    if let coa = account.storage.borrow<&EVM.CadenceOwnedAccount>(from: /storage/evm) {
+      coas[address] = coa.address().toString()
+    }
+  }
+  return coas
+}`, contracts.EVMContract.Address.Hex()),
+		overflow.WithArg("addresses", addresses),
+	)
+
+	var result map[string]string
+	if err := res.MarshalAs(&result); err != nil {
+		return nil, err
+	}
+	for address, coa := range result {
+		coas[address] = "0x" + strings.TrimPrefix(coa, "0x")
+	}
+	return coas, nil
+}
+
+// chainID returns the chain aether runs against, which decides where the system contracts are
+func chainID(cfg *aetherConfig.Config) flowgo.ChainID {
+	switch cfg.Network {
+	case "mainnet":
+		return flowgo.Mainnet
+	case "testnet":
+		return flowgo.Testnet
+	}
+	if fork, ok, _ := aetherConfig.ParseFork(cfg.Network); ok {
+		if fork.Network == "mainnet" {
+			return flowgo.Mainnet
+		}
+		return flowgo.Testnet
+	}
+	if cfg.Flow.Emulator.SimpleAddressesEnabled {
+		return flowgo.MonotonicEmulator
+	}
+	return flowgo.Emulator
+}
@@ -0,0 +1,109 @@
+package flow
+
+import (
+	"strings"
+
+	aetherConfig "github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/overflow/v2"
+)
+
+// TransactionFilter keeps the transactions that match the watchlist in indexer.filter, so following
+// a busy network like mainnet only shows what the project cares about
+type TransactionFilter struct {
+	addresses map[string]bool // Hex without 0x, lowercase and padded to 16 characters
+	contracts []string        // Normalized contract identifiers, A.<address>.<name>
+	events    []string        // Event type prefixes
+}
+
+// NewTransactionFilter returns the filter for cfg, or nil when it keeps every transaction
+func NewTransactionFilter(cfg aetherConfig.IndexerFilter) *TransactionFilter {
+	if cfg.IsEmpty() {
+		return nil
+	}
+
+	f := &TransactionFilter{addresses: make(map[string]bool)}
+	for _, address := range cfg.Addresses {
+		f.addresses[normalizeAddress(address)] = true
+	}
+	for _, contract := range cfg.Contracts {
+		f.contracts = append(f.contracts, normalizeIdentifier(contract))
+	}
+	for _, event := range cfg.Events {
+		f.events = append(f.events, normalizeIdentifier(strings.TrimSpace(event)))
+	}
+	return f
+}
+
+// Filter returns the transactions that are kept, a nil filter keeps all of them
+func (f *TransactionFilter) Filter(transactions []overflow.OverflowTransaction) []overflow.OverflowTransaction {
+	if f == nil {
+		return transactions
+	}
+	kept := make([]overflow.OverflowTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if f.Keep(tx) {
+			kept = append(kept, tx)
+		}
+	}
+	return kept
+}
+
+// Keep reports whether tx is signed by a watched address, imports a watched contract or emits a
+// watched event
+func (f *TransactionFilter) Keep(tx overflow.OverflowTransaction) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.addresses[normalizeAddress(tx.Payer)] || f.addresses[normalizeAddress(tx.ProposalKey.Address.Hex())] {
+		return true
+	}
+	for _, authorizer := range tx.Authorizers {
+		if f.addresses[normalizeAddress(authorizer)] {
+			return true
+		}
+	}
+
+	for _, imp := range tx.Imports {
+		identifier := normalizeIdentifier(imp.Identifier())
+		for _, contract := range f.contracts {
+			if identifier == contract {
+				return true
+			}
+		}
+	}
+
+	for _, event := range tx.Events {
+		name := normalizeIdentifier(event.Name)
+		for _, contract := range f.contracts {
+			if strings.HasPrefix(name, contract+".") {
+				return true
+			}
+		}
+		for _, prefix := range f.events {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeAddress makes addresses with and without 0x or leading zeros compare equal
+func normalizeAddress(address string) string {
+	address = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(address), "0x"))
+	if len(address) < 16 {
+		address = strings.Repeat("0", 16-len(address)) + address
+	}
+	return address
+}
+
+// normalizeIdentifier normalizes the address in a type identifier like A.0x1654653399040a61.FlowToken
+func normalizeIdentifier(identifier string) string {
+	parts := strings.SplitN(identifier, ".", 3)
+	if len(parts) < 3 || parts[0] != "A" {
+		return identifier
+	}
+	parts[1] = normalizeAddress(parts[1])
+	return strings.Join(parts, ".")
+}
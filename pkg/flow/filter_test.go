@@ -0,0 +1,61 @@
+package flow
+
+import (
+	"testing"
+
+	aetherConfig "github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/overflow/v2"
+	"github.com/onflow/flow-go-sdk"
+)
+
+func TestTransactionFilter(t *testing.T) {
+	topShot := overflow.OverflowTransaction{
+		Payer:       "0x18eb4ee6b3c026d2",
+		Authorizers: []string{"0x0b2a3299cc857e29"},
+		ProposalKey: flow.ProposalKey{Address: flow.HexToAddress("18eb4ee6b3c026d2")},
+		Imports:     []overflow.Import{{Address: "0x0b2a3299cc857e29", Name: "TopShot"}},
+	}
+	deposit := overflow.OverflowTransaction{
+		Payer:       "0x18eb4ee6b3c026d2",
+		Authorizers: []string{"0x01cf0e2f2f715450"},
+		ProposalKey: flow.ProposalKey{Address: flow.HexToAddress("01cf0e2f2f715450")},
+		Events:      []overflow.OverflowEvent{{Name: "A.1654653399040a61.FlowToken.TokensDeposited"}},
+	}
+
+	tests := []struct {
+		name   string
+		filter aetherConfig.IndexerFilter
+		want   []bool
+	}{
+		{"authorizer", aetherConfig.IndexerFilter{Addresses: []string{"0x0b2a3299cc857e29"}}, []bool{true, false}},
+		{"payer", aetherConfig.IndexerFilter{Addresses: []string{"18EB4EE6B3C026D2"}}, []bool{true, true}},
+		{"proposer", aetherConfig.IndexerFilter{Addresses: []string{"0x01cf0e2f2f715450"}}, []bool{false, true}},
+		{"imported contract", aetherConfig.IndexerFilter{Contracts: []string{"A.0x0b2a3299cc857e29.TopShot"}}, []bool{true, false}},
+		{"event of contract", aetherConfig.IndexerFilter{Contracts: []string{"A.1654653399040a61.FlowToken"}}, []bool{false, true}},
+		{"contract name prefix", aetherConfig.IndexerFilter{Contracts: []string{"A.0b2a3299cc857e29.Top"}}, []bool{false, false}},
+		{"event prefix", aetherConfig.IndexerFilter{Events: []string{"A.1654653399040a61.FlowToken.TokensDep"}}, []bool{false, true}},
+		{"no match", aetherConfig.IndexerFilter{Events: []string{"A.1654653399040a61.FlowToken.TokensWithdrawn"}}, []bool{false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewTransactionFilter(tt.filter)
+			for i, tx := range []overflow.OverflowTransaction{topShot, deposit} {
+				if got := f.Keep(tx); got != tt.want[i] {
+					t.Errorf("Keep(tx %d) = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionFilterEmpty(t *testing.T) {
+	f := NewTransactionFilter(aetherConfig.IndexerFilter{})
+	if f != nil {
+		t.Fatal("expected an empty filter to be nil")
+	}
+	transactions := []overflow.OverflowTransaction{{Payer: "0x01"}}
+	if got := f.Filter(transactions); len(got) != 1 {
+		t.Errorf("Filter() kept %d transactions, want 1", len(got))
+	}
+}
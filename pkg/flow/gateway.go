@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
+	"sync/atomic"
 
 	aetherConfig "github.com/bjartek/aether/pkg/config"
 	gethCrypto "github.com/ethereum/go-ethereum/crypto"
@@ -13,9 +15,9 @@ import (
 	gatewayConfig "github.com/onflow/flow-evm-gateway/config"
 	flowsdk "github.com/onflow/flow-go-sdk"
 	flowCrypto "github.com/onflow/flow-go-sdk/crypto"
-	"github.com/onflow/flow-go/fvm/evm/types"
 	flowGo "github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/accounts"
 	"github.com/onflow/flowkit/v2/config"
 	"github.com/rs/zerolog"
 	"github.com/spf13/afero"
@@ -28,6 +30,8 @@ type Gateway struct {
 	done   chan struct{}
 	dbPath string
 	logger zerolog.Logger
+
+	started atomic.Bool // Stop only waits for a gateway that was started
 }
 
 // InitGateway initializes the EVM gateway with configuration
@@ -43,36 +47,36 @@ func InitGateway(logger zerolog.Logger, cfg *aetherConfig.Config) (*Gateway, gat
 		return nil, gatewayConfig.Config{}, err
 	}
 
-	// Use the standard EVM gateway private key (same as flow-cli)
-	// This key is used for both COA operations and wallet API
-	gatewayKeyHex := "2619878f0e2ff438d17835c2a4561cb87b4d24d72d12ec34569acd0dd4af7c21"
-
-	// Parse as ECDSA private key for wallet
-	evmPrivateKey, err := gethCrypto.HexToECDSA(gatewayKeyHex)
+	// The wallet key signs eth_sendTransaction and its address collects the fees
+	evmPrivateKey, err := gethCrypto.HexToECDSA(strings.TrimPrefix(cfg.EVM.WalletKey, "0x"))
 	if err != nil {
-		return nil, gatewayConfig.Config{}, fmt.Errorf("failed to parse EVM private key: %w", err)
+		return nil, gatewayConfig.Config{}, fmt.Errorf("failed to parse EVM wallet key: %w", err)
 	}
-
-	// Parse as Flow private key for COA
-	flowPrivateKeyBytes, err := gethCrypto.HexToECDSA(gatewayKeyHex)
-	if err != nil {
-		return nil, gatewayConfig.Config{}, fmt.Errorf("failed to parse Flow private key: %w", err)
+	evmAddress := gethCrypto.PubkeyToAddress(evmPrivateKey.PublicKey)
+
+	// The COA account pays for the Flow transactions that wrap the EVM transactions
+	coaAccount := serviceAccount
+	if cfg.EVM.COAAccount != "" {
+		coaAccount, err = state.Accounts().ByName(cfg.EVM.COAAccount)
+		if err != nil {
+			return nil, gatewayConfig.Config{}, fmt.Errorf("evm.coa_account: %w", err)
+		}
 	}
-
-	// Convert ECDSA key to Flow crypto format
-	flowPrivateKey, err := flowCrypto.DecodePrivateKeyHex(flowCrypto.ECDSA_P256, gatewayKeyHex)
+	coaKey, err := coaPrivateKey(coaAccount, cfg.EVM.COAKeyFile)
 	if err != nil {
-		return nil, gatewayConfig.Config{}, fmt.Errorf("failed to decode Flow private key: %w", err)
+		return nil, gatewayConfig.Config{}, err
 	}
 
-	// Derive the EVM address from the private key
-	evmAddress := gethCrypto.PubkeyToAddress(flowPrivateKeyBytes.PublicKey)
-
 	// Get database path from config
 	dbPath := cfg.EVM.DatabasePath
 
 	// Create logger for gateway operations
-	logger.Info().Str("evmAddress", evmAddress.Hex()).Msg("Using EVM gateway key")
+	logger.Info().
+		Str("evmAddress", evmAddress.Hex()).
+		Str("coaAddress", coaAccount.Address.String()).
+		Uint64("chainID", cfg.EVM.ChainID).
+		Uint64("gasPrice", cfg.EVM.GasPrice).
+		Msg("Using EVM gateway key")
 
 	// Clean up old database if configured to do so
 	if cfg.EVM.DeleteDatabaseOnStart {
@@ -92,25 +96,25 @@ func InitGateway(logger zerolog.Logger, cfg *aetherConfig.Config) (*Gateway, gat
 		RPCPort:                cfg.Ports.EVM.RPC,
 		RPCHost:                "",
 		FlowNetworkID:          flowGo.Emulator,
-		EVMNetworkID:           types.FlowEVMPreviewNetChainID, // Chain ID 646
-		Coinbase:               evmAddress,                     // Use derived address from private key
-		WalletEnabled:          true,
-		EnforceGasPrice:        true,
+		EVMNetworkID:           new(big.Int).SetUint64(cfg.EVM.ChainID),
+		Coinbase:               evmAddress, // Use derived address from private key
+		WalletEnabled:          cfg.EVM.WalletEnabled,
+		EnforceGasPrice:        cfg.EVM.EnforceGasPrice,
 		WalletKey:              evmPrivateKey, // ECDSA private key for wallet API
-		GasPrice:               big.NewInt(0),
-		COAAddress:             flowsdk.Address(serviceAccount.Address),
-		COAKey:                 flowPrivateKey, // Flow private key for COA operations
+		GasPrice:               new(big.Int).SetUint64(cfg.EVM.GasPrice),
+		COAAddress:             flowsdk.Address(coaAccount.Address),
+		COAKey:                 coaKey, // Flow private key for COA operations
 		Logger:                 &logger,
-		TxStateValidation:      "local-index",
+		TxStateValidation:      cfg.EVM.TxStateValidation,
 		ProfilerEnabled:        true,
 		ProfilerHost:           "localhost",
 		ProfilerPort:           cfg.Ports.EVM.Profiler,
 		WSEnabled:              true,
 		MetricsPort:            cfg.Ports.EVM.Metrics,
-		FilterExpiry:           300000000000,
-		TxRequestLimitDuration: 300000000000,
-		TxBatchInterval:        1200000000,
-		EOAActivityCacheTTL:    10000000000,
+		FilterExpiry:           cfg.EVM.FilterExpiry,
+		TxRequestLimitDuration: cfg.EVM.TxRequestLimitDuration,
+		TxBatchInterval:        cfg.EVM.TxBatchInterval,
+		EOAActivityCacheTTL:    cfg.EVM.EOAActivityCacheTTL,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -127,8 +131,31 @@ func InitGateway(logger zerolog.Logger, cfg *aetherConfig.Config) (*Gateway, gat
 	return gateway, gatewayCfg, nil
 }
 
+// coaPrivateKey returns the key the gateway signs Flow transactions with, read from keyFile if set
+// and from the account in flow.json otherwise
+func coaPrivateKey(account *accounts.Account, keyFile string) (flowCrypto.PrivateKey, error) {
+	if keyFile == "" {
+		pk, err := account.Key.PrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the COA key of %s from flow.json: %w", account.Name, err)
+		}
+		return *pk, nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evm.coa_key_file: %w", err)
+	}
+	pk, err := flowCrypto.DecodePrivateKeyHex(account.Key.SigAlgo(), strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the COA key in %s: %w", keyFile, err)
+	}
+	return pk, nil
+}
+
 // Start starts the EVM gateway server
 func (g *Gateway) Start(cfg gatewayConfig.Config) {
+	g.started.Store(true)
 	go func() {
 		defer close(g.done)
 
@@ -185,11 +212,24 @@ func (g *Gateway) Ready() <-chan struct{} {
 	return g.ready
 }
 
+// Done returns a channel that will be closed when the gateway has stopped
+func (g *Gateway) Done() <-chan struct{} {
+	return g.done
+}
+
+// Stopping reports whether Stop was called, as opposed to the gateway exiting on its own
+func (g *Gateway) Stopping() bool {
+	return g.ctx.Err() != nil
+}
+
 // Stop stops the gateway and cleans up the database
 func (g *Gateway) Stop() {
 	if g.cancel != nil {
 		g.cancel()
 	}
+	if !g.started.Load() {
+		return
+	}
 	// Wait for gateway to fully stop
 	<-g.done
 }
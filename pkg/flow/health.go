@@ -0,0 +1,21 @@
+package flow
+
+import (
+	"context"
+
+	"github.com/bjartek/aether/pkg/health"
+	grpcAccess "github.com/onflow/flow-go-sdk/access/grpc"
+)
+
+// AccessProbe is ready when the Flow access API at addr answers a ping.
+// The returned close func releases the connection.
+func AccessProbe(addr string) (health.Probe, func() error, error) {
+	client, err := grpcAccess.NewClient(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	probe := func(ctx context.Context) error {
+		return client.Ping(ctx)
+	}
+	return probe, client.Close, nil
+}
@@ -12,16 +12,57 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// ErrChainReset is returned by StreamTransactions when the latest block is below the blocks it already streamed,
+// e.g. after the emulator was restarted without persisted state. The caller should index again from the start.
+var ErrChainReset = errors.New("chain was reset, the latest block is below the streamed height")
+
+// maxRetryBackoff caps the wait between retries of a block that could not be fetched
+const maxRetryBackoff = 30 * time.Second
+
 type BlockResult struct {
 	Block        flow.Block
 	Transactions []overflow.OverflowTransaction
 	Error        error
+	Failures     int           // Consecutive failures to fetch the block at this height, set with Error
+	RetryIn      time.Duration // Wait before the next attempt, set with Error
 	Logger       zerolog.Logger
 	View         uint64
 	StartTime    time.Time
 }
 
-func StreamTransactions(ctx context.Context, o *overflow.OverflowState, poll time.Duration, height uint64, logger *zerolog.Logger, channel chan<- BlockResult) error {
+// fetchError is how StreamTransactions handles a block it could not fetch
+type fetchError int
+
+const (
+	fetchRetry    fetchError = iota // Report the failure and try again after a backoff
+	fetchStop                       // The context was cancelled
+	fetchNotReady                   // The collection is not available yet, try again on the next tick
+)
+
+// classifyFetchError decides how err is handled, the same for prefetched blocks and the ones fetched one at a time
+func classifyFetchError(err error) fetchError {
+	// things can be wrapped
+	switch {
+	case strings.Contains(err.Error(), "context canceled"):
+		return fetchStop
+	case strings.Contains(err.Error(), "could not retrieve collection: key not found"):
+		return fetchNotReady
+	}
+	return fetchRetry
+}
+
+// retryBackoff doubles the poll interval for every consecutive failure, up to maxRetryBackoff
+func retryBackoff(poll time.Duration, failures int) time.Duration {
+	backoff := poll
+	for i := 0; i < failures && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// StreamTransactions polls for the blocks after height and sends them with their transactions to channel in
+// height order. When it is more than one block behind, up to concurrency blocks are fetched in parallel.
+func StreamTransactions(ctx context.Context, o *overflow.OverflowState, poll time.Duration, concurrency int, height uint64, logger *zerolog.Logger, channel chan<- BlockResult) error {
 	logger.Info().Msg("StreamTransactions started")
 	latestKnownBlock, err := o.GetLatestBlock(ctx)
 	if err != nil {
@@ -31,6 +72,23 @@ func StreamTransactions(ctx context.Context, o *overflow.OverflowState, poll tim
 	logger.Info().Uint64("height", latestKnownBlock.Height).Uint64("heightToStartAt", height).Dur("poll", poll).Msg("Starting to stream from latest block")
 
 	sleep := poll
+	failures := 0
+
+	// retry reports a failed fetch of the next block and waits longer before every new attempt,
+	// the height is kept so no block is skipped
+	retry := func(nextBlock uint64, err error, logg zerolog.Logger, start time.Time) bool {
+		failures++
+		sleep = retryBackoff(poll, failures)
+		result := BlockResult{Error: err, Failures: failures, RetryIn: sleep, Logger: logg, StartTime: start}
+		result.Block.Height = nextBlock
+		select {
+		case channel <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
 	for {
 		select {
 		case <-time.After(sleep):
@@ -45,30 +103,93 @@ func StreamTransactions(ctx context.Context, o *overflow.OverflowState, poll tim
 			logg := logger.With().Uint64("height", nextBlockToProcess).Uint64("latestKnownBlock", latestKnownBlock.Height).Logger()
 			logg.Debug().Msg("tick")
 
+			// Catching up on more than one block, fetch ahead in parallel but deliver in height order
+			if concurrency > 1 && nextBlockToProcess+1 < latestKnownBlock.Height {
+				logg.Debug().Int("concurrency", concurrency).Msg("catching up, prefetching blocks")
+				fetchCtx, cancelFetch := context.WithCancel(ctx)
+				sleep = time.Millisecond
+				fetch := func(ctx context.Context, height uint64) fetchedBlock {
+					return fetchBlock(ctx, o, height, *logger)
+				}
+			prefetch:
+				for fb := range prefetchBlocks(fetchCtx, nextBlockToProcess, latestKnownBlock.Height-1, concurrency, fetch) {
+					if fb.err != nil {
+						switch classifyFetchError(fb.err) {
+						case fetchStop:
+							cancelFetch()
+							return nil
+						case fetchNotReady:
+							// Fetched again with the blocks after it on the next tick
+							fb.logger.Debug().Err(fb.err).Msg("collection of prefetched block not available yet")
+							sleep = poll
+							break prefetch
+						}
+						fb.logger.Info().Err(fb.err).Msg("error prefetching block")
+						// The block can be gone because the chain started over
+						if latest, latestErr := o.GetLatestBlock(ctx); latestErr == nil && latest.Height < fb.height {
+							cancelFetch()
+							return ErrChainReset
+						}
+						if !retry(fb.height, fb.err, fb.logger, start) {
+							cancelFetch()
+							return ctx.Err()
+						}
+						break
+					}
+
+					failures = 0
+					select {
+					case channel <- BlockResult{Block: *fb.block, Transactions: fb.transactions, Logger: fb.logger, StartTime: start}:
+						height = fb.height
+					case <-ctx.Done():
+						cancelFetch()
+						return ctx.Err()
+					}
+				}
+				cancelFetch()
+				continue
+			}
+
 			var block *flow.Block
 			if nextBlockToProcess < latestKnownBlock.Height {
 				logg.Debug().Msg("next block is smaller then latest known block")
 				// we are still processing historical blocks
 				block, err = o.GetBlockAtHeight(ctx, nextBlockToProcess)
 				if err != nil {
-					// things can be wrapped
-					if strings.Contains(err.Error(), "context canceled") {
+					if classifyFetchError(err) == fetchStop {
 						return nil
 					}
 					logg.Info().Err(err).Str("raw error", err.Error()).Msg("error fetching old block")
+					// The block can be gone because the chain started over
+					if latest, latestErr := o.GetLatestBlock(ctx); latestErr == nil && latest.Height < nextBlockToProcess {
+						return ErrChainReset
+					}
+					if !retry(nextBlockToProcess, errors.Wrap(err, "getting block"), logg, start) {
+						return ctx.Err()
+					}
 					continue
 				}
 			} else if nextBlockToProcess != latestKnownBlock.Height {
 				logg.Debug().Msg("next block is not equal to latest block")
 				block, err = o.GetLatestBlock(ctx)
 				if err != nil {
+					if classifyFetchError(err) == fetchStop {
+						return nil
+					}
 					logg.Info().Err(err).Msg("error fetching latest block, retrying")
+					if !retry(nextBlockToProcess, errors.Wrap(err, "getting latest block"), logg, start) {
+						return ctx.Err()
+					}
 					continue
 				}
 
 				if block == nil || block.Height == latestKnownBlock.Height {
 					continue
 				}
+				if block.Height < height {
+					logg.Warn().Uint64("latestBlock", block.Height).Msg("latest block is below the streamed height, the chain was reset")
+					return ErrChainReset
+				}
 				latestKnownBlock = block
 				// we just continue the next iteration in the loop here
 				sleep = time.Millisecond
@@ -84,24 +205,20 @@ func StreamTransactions(ctx context.Context, o *overflow.OverflowState, poll tim
 			logg.Debug().Uint64("height", block.Height).Str("blockID", block.ID.String()).Msg("Fetching transactions for block...")
 			transactions, err := GetOverflowTransactionsForBlockID(ctx, o, block.ID, logg)
 			if err != nil {
-				if strings.Contains(err.Error(), "context canceled") {
+				switch classifyFetchError(err) {
+				case fetchStop:
 					return nil
-				}
-				if strings.Contains(err.Error(), "could not retrieve collection: key not found") {
+				case fetchNotReady:
 					continue
 				}
 
 				logg.Debug().Err(err).Msg("getting transaction")
-
-				select {
-				case channel <- BlockResult{Block: *block, Error: errors.Wrap(err, "getting transactions"), Logger: logg, View: 0, StartTime: start}:
-					height = nextBlockToProcess
-				case <-ctx.Done():
-					close(channel)
+				if !retry(nextBlockToProcess, errors.Wrap(err, "getting transactions"), logg, start) {
 					return ctx.Err()
 				}
 				continue
 			}
+			failures = 0
 			logg = logg.With().Int("tx", len(transactions)).Logger()
 			logg.Debug().Msg("fetched transactions")
 
@@ -130,9 +247,18 @@ func StreamTransactions(ctx context.Context, o *overflow.OverflowState, poll tim
 	}
 }
 
-func GetOverflowTransactionsForBlockID(ctx context.Context, o overflow.OverflowClient, id flow.Identifier, logg zerolog.Logger) ([]overflow.OverflowTransaction, error) {
-	transactions := []overflow.OverflowTransaction{}
+// createTransaction builds an overflow transaction from a transaction and its result, like
+// OverflowClient.CreateOverflowTransaction
+type createTransaction func(blockID string, result flow.TransactionResult, transaction flow.Transaction, txIndex int) (*overflow.OverflowTransaction, error)
+
+// blockTransaction is a transaction of a block and its result
+type blockTransaction struct {
+	transaction flow.Transaction
+	result      flow.TransactionResult
+	system      bool // Part of the system collection, e.g. the execution of a scheduled transaction
+}
 
+func GetOverflowTransactionsForBlockID(ctx context.Context, o overflow.OverflowClient, id flow.Identifier, logg zerolog.Logger) ([]overflow.OverflowTransaction, error) {
 	logg.Debug().Str("blockId", id.String()).Msg("Fetching transactions for block")
 	tx, txR, err := o.GetTransactionsByBlockId(ctx, id)
 	if err != nil {
@@ -140,11 +266,20 @@ func GetOverflowTransactionsForBlockID(ctx context.Context, o overflow.OverflowC
 	}
 
 	logg.Info().Str("blockId", id.String()).Int("tx", len(tx)).Int("txR", len(txR)).Msg("Fetched tx")
-	for i, rp := range txR {
+	txs := make([]blockTransaction, len(txR))
+	for i, r := range txR {
+		txs[i] = blockTransaction{transaction: *tx[i], result: *r, system: r.CollectionID == flow.EmptyID}
+	}
+	return overflowTransactions(o.CreateOverflowTransaction, id, txs, logg), nil
+}
 
-		t := *tx[i]
-		r := *rp
-		if r.CollectionID == flow.EmptyID {
+// overflowTransactions turns the transactions of a block into overflow transactions. System transactions
+// are only kept when they executed a scheduled transaction.
+func overflowTransactions(create createTransaction, id flow.Identifier, txs []blockTransaction, logg zerolog.Logger) []overflow.OverflowTransaction {
+	transactions := []overflow.OverflowTransaction{}
+	for i, bt := range txs {
+		r := bt.result
+		if bt.system {
 			keep := false
 			if len(r.Events) > 0 {
 				for _, e := range r.Events {
@@ -166,7 +301,7 @@ func GetOverflowTransactionsForBlockID(ctx context.Context, o overflow.OverflowC
 		logg.Debug().Str("collection", r.CollectionID.Hex()).Int("txIndex", i).Msg("Processing transaction")
 
 		txLogger := logg.With().Str("txid", r.TransactionID.Hex()).Logger()
-		ot, err := o.CreateOverflowTransaction(id.String(), r, t, i)
+		ot, err := create(id.String(), r, bt.transaction, i)
 		if err != nil {
 			txLogger.Error().Err(err).Msg("Failed to create overflow transaction - skipping this transaction")
 			// Don't panic - just skip this transaction and continue processing
@@ -180,5 +315,5 @@ func GetOverflowTransactionsForBlockID(ctx context.Context, o overflow.OverflowC
 		transactions = append(transactions, *ot)
 	}
 
-	return transactions, nil
+	return transactions
 }
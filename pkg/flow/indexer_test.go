@@ -0,0 +1,31 @@
+package flow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestClassifyFetchError(t *testing.T) {
+	notReady := errors.New("rpc error: code = NotFound desc = could not retrieve collection: key not found")
+	tests := []struct {
+		name string
+		err  error
+		want fetchError
+	}{
+		{"cancelled", errors.Wrap(context.Canceled, "getting block"), fetchStop},
+		// Prefetched blocks wrap the error once more than the ones fetched one at a time
+		{"collection not ready", errors.Wrap(notReady, "getting transaction results"), fetchNotReady},
+		{"prefetched collection not ready", errors.Wrap(errors.Wrap(notReady, "getting transaction results"), "getting transactions"), fetchNotReady},
+		{"other", errors.New("connection refused"), fetchRetry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFetchError(tt.err); got != tt.want {
+				t.Errorf("classifyFetchError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
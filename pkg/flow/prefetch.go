@@ -0,0 +1,76 @@
+package flow
+
+import (
+	"context"
+
+	"github.com/bjartek/overflow/v2"
+	"github.com/cockroachdb/errors"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+)
+
+// fetchedBlock is a block and its transactions fetched ahead by prefetchBlocks
+type fetchedBlock struct {
+	height       uint64
+	block        *flow.Block
+	transactions []overflow.OverflowTransaction
+	err          error
+	logger       zerolog.Logger
+}
+
+// prefetchBlocks fetches the blocks from..to with at most workers blocks in flight and sends them to the
+// returned channel in height order. It stops after the first block that fails, cancel ctx to stop early.
+func prefetchBlocks(ctx context.Context, from, to uint64, workers int, fetch func(context.Context, uint64) fetchedBlock) <-chan fetchedBlock {
+	// Every height gets a slot, the buffer of pending bounds how far ahead of the receiver we fetch
+	pending := make(chan chan fetchedBlock, workers)
+	go func() {
+		defer close(pending)
+		for height := from; height <= to; height++ {
+			slot := make(chan fetchedBlock, 1)
+			select {
+			case pending <- slot:
+			case <-ctx.Done():
+				return
+			}
+			go func(height uint64) {
+				slot <- fetch(ctx, height)
+			}(height)
+		}
+	}()
+
+	out := make(chan fetchedBlock)
+	go func() {
+		defer close(out)
+		for slot := range pending {
+			var fb fetchedBlock
+			select {
+			case fb = <-slot:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case out <- fb:
+			case <-ctx.Done():
+				return
+			}
+			if fb.err != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// fetchBlock fetches the block at height and its transactions
+func fetchBlock(ctx context.Context, o *overflow.OverflowState, height uint64, logger zerolog.Logger) fetchedBlock {
+	logg := logger.With().Uint64("height", height).Logger()
+	block, err := o.GetBlockAtHeight(ctx, height)
+	if err != nil {
+		return fetchedBlock{height: height, err: errors.Wrap(err, "getting block"), logger: logg}
+	}
+	transactions, err := GetOverflowTransactionsForBlockID(ctx, o, block.ID, logg)
+	if err != nil {
+		return fetchedBlock{height: height, block: block, err: errors.Wrap(err, "getting transactions"), logger: logg}
+	}
+	return fetchedBlock{height: height, block: block, transactions: transactions, logger: logg}
+}
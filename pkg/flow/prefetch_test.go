@@ -0,0 +1,67 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPrefetchBlocksInOrder(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	fetch := func(ctx context.Context, height uint64) fetchedBlock {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := maxInFlight.Load()
+			if n <= highest || maxInFlight.CompareAndSwap(highest, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+		return fetchedBlock{height: height}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	want := uint64(10)
+	for fb := range prefetchBlocks(ctx, 10, 59, 4, fetch) {
+		if fb.height != want {
+			t.Fatalf("got block %d, want %d", fb.height, want)
+		}
+		want++
+	}
+	if want != 60 {
+		t.Errorf("got blocks up to %d, want 59", want-1)
+	}
+	// The receiver waits for one slot while the buffer holds the next ones
+	if highest := maxInFlight.Load(); highest > 4+2 {
+		t.Errorf("%d blocks fetched at once, want at most %d", highest, 4+2)
+	}
+}
+
+func TestPrefetchBlocksStopsAtError(t *testing.T) {
+	fetch := func(ctx context.Context, height uint64) fetchedBlock {
+		if height == 5 {
+			return fetchedBlock{height: height, err: errors.New("unavailable")}
+		}
+		return fetchedBlock{height: height}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []uint64
+	for fb := range prefetchBlocks(ctx, 1, 20, 3, fetch) {
+		got = append(got, fb.height)
+		if fb.err != nil && fb.height != 5 {
+			t.Errorf("unexpected error for block %d", fb.height)
+		}
+	}
+	if len(got) != 5 || got[4] != 5 {
+		t.Errorf("got blocks %v, want 1-5", got)
+	}
+}
@@ -0,0 +1,171 @@
+package flow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bjartek/overflow/v2"
+)
+
+// ScriptDirs are the folders scanned for scripts, relative to the project root
+var ScriptDirs = []string{"scripts", "cadence/scripts"}
+
+// TransactionDirs are the folders scanned for transactions, relative to the project root
+var TransactionDirs = []string{"transactions", "cadence/transactions"}
+
+// networkSuffixes are the optional filename suffixes that pin a file to a network
+var networkSuffixes = []string{".emulator", ".testnet", ".mainnet"}
+
+// FindCdcFile finds a .cdc file by name in the given directories
+// It tries the plain name first and then each network suffix
+func FindCdcFile(name string, dirs []string) string {
+	for _, dir := range dirs {
+		// Try with and without network suffixes
+		for _, suffix := range append([]string{""}, networkSuffixes...) {
+			path := filepath.Join(dir, name+suffix+".cdc")
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// DetectNetwork determines the network from a filename without extension
+func DetectNetwork(filename string) string {
+	for _, suffix := range networkSuffixes {
+		if strings.HasSuffix(filename, suffix) {
+			return strings.TrimPrefix(suffix, ".")
+		}
+	}
+	return "any"
+}
+
+// RemoveNetworkSuffix removes network suffix from filename for display
+func RemoveNetworkSuffix(filename string) string {
+	for _, suffix := range networkSuffixes {
+		if strings.HasSuffix(filename, suffix) {
+			return strings.TrimSuffix(filename, suffix)
+		}
+	}
+	return filename
+}
+
+// Options converts the config into overflow interaction options
+// The first signer is the proposer/payer/authorizer, the rest are payload signers
+func (c *TransactionConfig) Options() []overflow.OverflowInteractionOption {
+	var opts []overflow.OverflowInteractionOption
+
+	// Add signers - first signer uses WithSigner, rest use WithPayloadSigner
+	for i, signer := range c.Signers {
+		if i == 0 {
+			opts = append(opts, overflow.WithSigner(signer))
+		} else {
+			opts = append(opts, overflow.WithPayloadSigner(signer))
+		}
+	}
+
+	// Add arguments
+	for argName, argValue := range c.Arguments {
+		opts = append(opts, overflow.WithArg(argName, argValue))
+	}
+	return opts
+}
+
+// Target is a script or transaction resolved from a name, .cdc path or .json config
+type Target struct {
+	Config   TransactionConfig // Name to execute plus signers/arguments from a .json config
+	Path     string            // Path of the .cdc file
+	IsScript bool
+	BasePath string // "cadence" if the file lives under cadence/, otherwise ""
+}
+
+type targetKind struct {
+	dirs     []string
+	isScript bool
+}
+
+var (
+	scriptKind      = targetKind{dirs: ScriptDirs, isScript: true}
+	transactionKind = targetKind{dirs: TransactionDirs, isScript: false}
+)
+
+// ResolveTarget resolves what to run from a command line argument. It accepts
+//   - a saved .json config, e.g. transactions/aliceSays.json
+//   - a path to a .cdc file, e.g. cadence/scripts/GetCounter.cdc
+//   - a plain name as shown in the runner, e.g. GetCounter or nested/GetCounter
+//
+// Names are looked up in the same folders as the runner, scripts first.
+func ResolveTarget(target string) (*Target, error) {
+	switch filepath.Ext(target) {
+	case ".json":
+		config, err := LoadTransactionConfig(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config %q: %w", target, err)
+		}
+		if config.Arguments == nil {
+			config.Arguments = map[string]interface{}{}
+		}
+
+		// Prefer the kind of folder the config lives in
+		kinds := []targetKind{transactionKind, scriptKind}
+		if inDirs(target, ScriptDirs) {
+			kinds = []targetKind{scriptKind, transactionKind}
+		}
+		for _, kind := range kinds {
+			if path := FindCdcFile(config.Name, kind.dirs); path != "" {
+				return newTarget(*config, path, kind.isScript), nil
+			}
+		}
+		return nil, fmt.Errorf("config %q references %q which was not found", target, config.Name)
+
+	case ".cdc":
+		if _, err := os.Stat(target); err != nil {
+			return nil, err
+		}
+		for _, kind := range []targetKind{scriptKind, transactionKind} {
+			for _, dir := range kind.dirs {
+				rel, err := filepath.Rel(dir, target)
+				if err != nil || strings.HasPrefix(rel, "..") {
+					continue
+				}
+				name := RemoveNetworkSuffix(strings.TrimSuffix(rel, ".cdc"))
+				return newTarget(TransactionConfig{Name: name, Arguments: map[string]interface{}{}}, target, kind.isScript), nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not inside any of %v", target, append(ScriptDirs, TransactionDirs...))
+	}
+
+	for _, kind := range []targetKind{scriptKind, transactionKind} {
+		if path := FindCdcFile(target, kind.dirs); path != "" {
+			return newTarget(TransactionConfig{Name: target, Arguments: map[string]interface{}{}}, path, kind.isScript), nil
+		}
+	}
+	return nil, fmt.Errorf("no script or transaction named %q found", target)
+}
+
+func newTarget(config TransactionConfig, path string, isScript bool) *Target {
+	basePath := ""
+	if inDirs(path, []string{"cadence"}) {
+		basePath = "cadence"
+	}
+	return &Target{
+		Config:   config,
+		Path:     path,
+		IsScript: isScript,
+		BasePath: basePath,
+	}
+}
+
+// inDirs reports whether path is located below one of dirs
+func inDirs(path string, dirs []string) bool {
+	clean := filepath.Clean(path)
+	for _, dir := range dirs {
+		if strings.HasPrefix(clean, filepath.Clean(dir)+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
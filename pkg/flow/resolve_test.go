@@ -0,0 +1,71 @@
+package flow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolveTarget verifies that `aether run` finds files the same way the runner does
+//
+// Directory structure:
+//
+//	cadence/
+//	├── scripts/
+//	│   ├── GetCounter.cdc
+//	│   └── nested/GetOther.testnet.cdc
+//	└── transactions/
+//	    ├── aliceSays.cdc
+//	    └── aliceSays.json   (name: aliceSays, signers: [alice])
+func TestResolveTarget(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	scripts := filepath.Join("cadence", "scripts")
+	transactions := filepath.Join("cadence", "transactions")
+	require.NoError(t, os.MkdirAll(filepath.Join(scripts, "nested"), 0755))
+	require.NoError(t, os.MkdirAll(transactions, 0755))
+
+	createFile(t, scripts, "GetCounter.cdc", "access(all) fun main(): Int { return 1 }")
+	createFile(t, filepath.Join(scripts, "nested"), "GetOther.testnet.cdc", "access(all) fun main(): Int { return 2 }")
+	createFile(t, transactions, "aliceSays.cdc", "transaction(message: String) {}")
+	createFile(t, transactions, "aliceSays.json", `{"name":"aliceSays","signers":["alice"],"arguments":{"message":"hi"}}`)
+
+	t.Run("plain name", func(t *testing.T) {
+		target, err := ResolveTarget("GetCounter")
+		require.NoError(t, err)
+		assert.True(t, target.IsScript)
+		assert.Equal(t, "GetCounter", target.Config.Name)
+		assert.Equal(t, "cadence", target.BasePath)
+	})
+
+	t.Run("nested name with network suffix", func(t *testing.T) {
+		target, err := ResolveTarget("nested/GetOther")
+		require.NoError(t, err)
+		assert.True(t, target.IsScript)
+		assert.Equal(t, filepath.Join(scripts, "nested", "GetOther.testnet.cdc"), target.Path)
+	})
+
+	t.Run("cdc path", func(t *testing.T) {
+		target, err := ResolveTarget(filepath.Join(scripts, "nested", "GetOther.testnet.cdc"))
+		require.NoError(t, err)
+		assert.Equal(t, "nested/GetOther", filepath.ToSlash(target.Config.Name))
+	})
+
+	t.Run("json config", func(t *testing.T) {
+		target, err := ResolveTarget(filepath.Join(transactions, "aliceSays.json"))
+		require.NoError(t, err)
+		assert.False(t, target.IsScript)
+		assert.Equal(t, "aliceSays", target.Config.Name)
+		assert.Equal(t, []string{"alice"}, target.Config.Signers)
+		assert.Equal(t, "hi", target.Config.Arguments["message"])
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		_, err := ResolveTarget("DoesNotExist")
+		assert.Error(t, err)
+	})
+}
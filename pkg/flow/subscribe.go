@@ -0,0 +1,169 @@
+package flow
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bjartek/overflow/v2"
+	"github.com/cockroachdb/errors"
+	"github.com/onflow/flow-go-sdk"
+	grpcAccess "github.com/onflow/flow-go-sdk/access/grpc"
+	"github.com/rs/zerolog"
+)
+
+// executionDataClient is the part of the access API client SubscribeTransactions uses
+type executionDataClient interface {
+	GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.BlockHeader, error)
+	SubscribeExecutionDataByBlockHeight(ctx context.Context, startHeight uint64) (<-chan *flow.ExecutionDataStreamResponse, <-chan error, error)
+	GetTransactionResult(ctx context.Context, txID flow.Identifier) (*flow.TransactionResult, error)
+}
+
+// SubscribeTransactions streams blocks like StreamTransactions, but from the execution data subscription of
+// the access API at host instead of polling for them. The execution data of a block has its transactions,
+// their events and results, so nothing has to be fetched per block. If the subscription can not be started
+// or breaks, it falls back to StreamTransactions from the last block it sent.
+func SubscribeTransactions(ctx context.Context, o *overflow.OverflowState, host string, poll time.Duration, concurrency int, height uint64, logger *zerolog.Logger, channel chan<- BlockResult) error {
+	fallback := func(height uint64, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Warn().Err(err).Uint64("height", height).Msg("Execution data subscription failed, falling back to polling")
+		return StreamTransactions(ctx, o, poll, concurrency, height, logger, channel)
+	}
+
+	client, err := grpcAccess.NewClient(host)
+	if err != nil {
+		return fallback(height, errors.Wrap(err, "connecting to access API"))
+	}
+	defer func() { _ = client.Close() }()
+
+	logger.Info().Str("host", host).Msg("Subscribing to execution data")
+	return subscribeTransactions(ctx, client, o.CreateOverflowTransaction, height, logger, channel, fallback)
+}
+
+// subscribeTransactions sends the blocks after height from the execution data subscription of client to
+// channel. fallback is called with the last block sent when the subscription can not be used.
+func subscribeTransactions(ctx context.Context, client executionDataClient, create createTransaction, height uint64, logger *zerolog.Logger, channel chan<- BlockResult, fallback func(height uint64, err error) error) error {
+	// The subscription is cancelled when we fall back so the access node can release it
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fail := func(err error) error {
+		cancel()
+		return fallback(height, err)
+	}
+
+	next := height + 1
+	if height == 0 {
+		latest, err := client.GetLatestBlockHeader(ctx, true)
+		if err != nil {
+			return fail(errors.Wrap(err, "getting latest block"))
+		}
+		next = latest.Height
+	}
+
+	responses, errs, err := client.SubscribeExecutionDataByBlockHeight(subCtx, next)
+	if err != nil {
+		return fail(errors.Wrap(err, "subscribing to execution data"))
+	}
+	logger.Info().Uint64("heightToStartAt", next).Msg("Subscribed to execution data")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-errs:
+			if !ok {
+				err = errors.New("subscription closed")
+			}
+			return fail(err)
+
+		case resp, ok := <-responses:
+			if !ok {
+				return fail(errors.New("subscription closed"))
+			}
+
+			start := time.Now()
+			logg := logger.With().Uint64("height", resp.Height).Logger()
+			if resp.Height != next {
+				logg.Warn().Uint64("expected", next).Msg("Subscription skipped blocks")
+				return fail(errors.Newf("expected block %d, got %d", next, resp.Height))
+			}
+			if resp.ExecutionData == nil {
+				return fail(errors.Newf("no execution data for block %d", resp.Height))
+			}
+
+			transactions := executionDataTransactions(ctx, client, create, resp, logg)
+			if ctx.Err() != nil {
+				return nil
+			}
+			logg = logg.With().Int("tx", len(transactions)).Logger()
+
+			block := flow.Block{BlockHeader: flow.BlockHeader{
+				ID:        resp.ExecutionData.BlockID,
+				Height:    resp.Height,
+				Timestamp: resp.BlockTimestamp,
+				Status:    flow.BlockStatusSealed,
+			}}
+			select {
+			case channel <- BlockResult{Block: block, Transactions: transactions, Logger: logg, StartTime: start}:
+				height = resp.Height
+				next = height + 1
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// executionDataTransactions builds the transactions of a block from its execution data. Only the error
+// message of a failed transaction is not part of it and is fetched from client.
+func executionDataTransactions(ctx context.Context, client executionDataClient, create createTransaction, resp *flow.ExecutionDataStreamResponse, logg zerolog.Logger) []overflow.OverflowTransaction {
+	data := resp.ExecutionData
+	events := make(map[flow.Identifier][]flow.Event)
+	for _, chunk := range data.ChunkExecutionData {
+		for _, event := range chunk.Events {
+			events[event.TransactionID] = append(events[event.TransactionID], *event)
+		}
+	}
+
+	var txs []blockTransaction
+	for i, chunk := range data.ChunkExecutionData {
+		// The last chunk is the system chunk, it also executes the scheduled transactions
+		system := i == len(data.ChunkExecutionData)-1
+		for j, tx := range chunk.Transactions {
+			if j >= len(chunk.TransactionResults) {
+				logg.Warn().Int("chunk", i).Msg("Execution data has fewer results than transactions")
+				break
+			}
+			light := chunk.TransactionResults[j]
+			result := flow.TransactionResult{
+				// Access nodes stream the execution data of sealed blocks
+				Status:           flow.TransactionStatusSealed,
+				Events:           events[light.TransactionID],
+				BlockID:          data.BlockID,
+				BlockHeight:      resp.Height,
+				TransactionID:    light.TransactionID,
+				ComputationUsage: light.ComputationUsed,
+			}
+			if light.Failed {
+				result.Error = transactionError(ctx, client, light.TransactionID, logg)
+			}
+			txs = append(txs, blockTransaction{transaction: *tx, result: result, system: system})
+		}
+	}
+	return overflowTransactions(create, data.BlockID, txs, logg)
+}
+
+// transactionError fetches why a transaction failed, the execution data only says that it did
+func transactionError(ctx context.Context, client executionDataClient, id flow.Identifier, logg zerolog.Logger) error {
+	result, err := client.GetTransactionResult(ctx, id)
+	if err != nil && !strings.Contains(err.Error(), "context canceled") {
+		logg.Debug().Err(err).Str("txid", id.Hex()).Msg("Failed to get the error of a failed transaction")
+	}
+	if err != nil || result.Error == nil {
+		return errors.New("transaction failed")
+	}
+	return result.Error
+}
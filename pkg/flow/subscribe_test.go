@@ -0,0 +1,188 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bjartek/overflow/v2"
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+)
+
+// fakeExecutionDataClient streams the responses it was given and then closes the subscription
+type fakeExecutionDataClient struct {
+	latest       uint64
+	responses    []*flow.ExecutionDataStreamResponse
+	subscribeErr error
+	errors       map[flow.Identifier]error // Errors of failed transactions
+	startedAt    uint64
+}
+
+func (c *fakeExecutionDataClient) GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.BlockHeader, error) {
+	return &flow.BlockHeader{Height: c.latest}, nil
+}
+
+func (c *fakeExecutionDataClient) SubscribeExecutionDataByBlockHeight(ctx context.Context, startHeight uint64) (<-chan *flow.ExecutionDataStreamResponse, <-chan error, error) {
+	if c.subscribeErr != nil {
+		return nil, nil, c.subscribeErr
+	}
+	c.startedAt = startHeight
+	responses := make(chan *flow.ExecutionDataStreamResponse, len(c.responses))
+	for _, resp := range c.responses {
+		responses <- resp
+	}
+	close(responses)
+	return responses, make(chan error), nil
+}
+
+func (c *fakeExecutionDataClient) GetTransactionResult(ctx context.Context, txID flow.Identifier) (*flow.TransactionResult, error) {
+	return &flow.TransactionResult{Error: c.errors[txID]}, nil
+}
+
+func testTransaction(payer string) *flow.Transaction {
+	return flow.NewTransaction().
+		SetScript([]byte("transaction {}")).
+		SetPayer(flow.HexToAddress(payer)).
+		SetProposalKey(flow.HexToAddress(payer), 0, 0)
+}
+
+func testEvent(tx *flow.Transaction, typ string, index int) *flow.Event {
+	eventType := cadence.NewEventType(nil, typ, nil, nil)
+	return &flow.Event{
+		Type:          typ,
+		TransactionID: tx.ID(),
+		EventIndex:    index,
+		Value:         cadence.NewEvent(nil).WithType(eventType),
+	}
+}
+
+// testBlock is the execution data of a block with a collection and the system chunk
+func testBlock(height uint64, collection []*flow.Transaction, failed map[flow.Identifier]bool, system []*flow.Transaction, events []*flow.Event) *flow.ExecutionDataStreamResponse {
+	chunk := func(txs []*flow.Transaction) *flow.ChunkExecutionData {
+		c := &flow.ChunkExecutionData{Transactions: txs}
+		for _, tx := range txs {
+			c.TransactionResults = append(c.TransactionResults, &flow.LightTransactionResult{TransactionID: tx.ID(), Failed: failed[tx.ID()]})
+		}
+		return c
+	}
+	collectionChunk, systemChunk := chunk(collection), chunk(system)
+	collectionChunk.Events = events
+	return &flow.ExecutionDataStreamResponse{
+		Height:         height,
+		BlockTimestamp: time.Unix(1700000000, 0),
+		ExecutionData: &flow.ExecutionData{
+			BlockID:            flow.HexToID("0a"),
+			ChunkExecutionData: []*flow.ChunkExecutionData{collectionChunk, systemChunk},
+		},
+	}
+}
+
+func testCreate(blockID string, result flow.TransactionResult, transaction flow.Transaction, txIndex int) (*overflow.OverflowTransaction, error) {
+	events := make([]overflow.OverflowEvent, len(result.Events))
+	for i, e := range result.Events {
+		events[i] = overflow.OverflowEvent{Name: e.Type}
+	}
+	return &overflow.OverflowTransaction{
+		Id:               result.TransactionID.String(),
+		BlockId:          blockID,
+		TransactionIndex: txIndex,
+		ProposalKey:      transaction.ProposalKey,
+		Events:           events,
+		Error:            result.Error,
+	}, nil
+}
+
+func TestSubscribeTransactions(t *testing.T) {
+	ok := testTransaction("01")
+	failed := testTransaction("02")
+	heartbeat := testTransaction("f8d6e0586b0a20c7")
+	client := &fakeExecutionDataClient{
+		responses: []*flow.ExecutionDataStreamResponse{
+			testBlock(11, []*flow.Transaction{ok, failed}, map[flow.Identifier]bool{failed.ID(): true},
+				[]*flow.Transaction{heartbeat}, []*flow.Event{testEvent(ok, "A.01.Counter.Incremented", 0)}),
+			testBlock(12, nil, nil, []*flow.Transaction{heartbeat}, nil),
+		},
+		errors: map[flow.Identifier]error{failed.ID(): errors.New("panic: not enough balance")},
+	}
+
+	channel := make(chan BlockResult, 10)
+	var fellBackAt uint64
+	fallback := func(height uint64, err error) error {
+		fellBackAt = height
+		return nil
+	}
+	logger := zerolog.Nop()
+	if err := subscribeTransactions(context.Background(), client, testCreate, 10, &logger, channel, fallback); err != nil {
+		t.Fatalf("subscribeTransactions() error = %v", err)
+	}
+	close(channel)
+
+	if client.startedAt != 11 {
+		t.Errorf("subscribed from %d, want 11", client.startedAt)
+	}
+	// The stream closes after the last block, so polling takes over from there
+	if fellBackAt != 12 {
+		t.Errorf("fell back at %d, want 12", fellBackAt)
+	}
+
+	var results []BlockResult
+	for result := range channel {
+		results = append(results, result)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(results))
+	}
+
+	first := results[0]
+	if first.Block.Height != 11 || first.Block.ID != flow.HexToID("0a") || first.Block.Timestamp.IsZero() {
+		t.Errorf("block = %+v, want height 11 with the id and timestamp of the execution data", first.Block.BlockHeader)
+	}
+	// The system transaction did not execute a scheduled transaction, so it is dropped
+	if len(first.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(first.Transactions))
+	}
+	if tx := first.Transactions[0]; tx.Id != ok.ID().String() || len(tx.Events) != 1 || tx.Error != nil {
+		t.Errorf("first transaction = %+v, want %s with its event", tx, ok.ID())
+	}
+	if tx := first.Transactions[1]; tx.Id != failed.ID().String() || tx.Error == nil || tx.Error.Error() != "panic: not enough balance" {
+		t.Errorf("failed transaction error = %v, want the error of its result", tx.Error)
+	}
+	if len(results[1].Transactions) != 0 {
+		t.Errorf("empty block has %d transactions", len(results[1].Transactions))
+	}
+}
+
+func TestSubscribeTransactionsFallsBack(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *fakeExecutionDataClient
+		height uint64
+		want   uint64
+	}{
+		{"subscription not supported", &fakeExecutionDataClient{subscribeErr: errors.New("unimplemented")}, 5, 5},
+		{"skipped block", &fakeExecutionDataClient{responses: []*flow.ExecutionDataStreamResponse{testBlock(6, nil, nil, nil, nil), testBlock(8, nil, nil, nil, nil)}}, 5, 6},
+		{"from latest", &fakeExecutionDataClient{latest: 20, responses: []*flow.ExecutionDataStreamResponse{testBlock(20, nil, nil, nil, nil)}}, 0, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fellBack := false
+			var fellBackAt uint64
+			fallback := func(height uint64, err error) error {
+				fellBack, fellBackAt = true, height
+				return nil
+			}
+			logger := zerolog.Nop()
+			channel := make(chan BlockResult, 10)
+			if err := subscribeTransactions(context.Background(), tt.client, testCreate, tt.height, &logger, channel, fallback); err != nil {
+				t.Fatalf("subscribeTransactions() error = %v", err)
+			}
+			if !fellBack || fellBackAt != tt.want {
+				t.Errorf("fell back = %v at %d, want at %d", fellBack, fellBackAt, tt.want)
+			}
+		})
+	}
+}
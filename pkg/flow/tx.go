@@ -1,6 +1,7 @@
 package flow
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,82 +29,72 @@ func RunInitTransactions(cdcOverflow *overflow.OverflowState, jsonOverflow *over
 			return nil
 		}
 
-		ext := filepath.Ext(info.Name())
-		
-		// Handle .json configuration files
-		if ext == ".json" {
-			baseName := strings.TrimSuffix(info.Name(), ext)
-			config, err := LoadTransactionConfig(path)
-			if err != nil {
-				logger.Error().Err(err).Str("file", info.Name()).Msg("Failed to load transaction config")
-				if progressCallback != nil {
-					progressCallback(baseName, false, err.Error(), "")
-				}
-				return err
-			}
+		return RunInitTransaction(cdcOverflow, jsonOverflow, path, logger, progressCallback)
+	})
+	return err
+}
 
-			// Build overflow options from config
-			var opts []overflow.OverflowInteractionOption
-			
-			// Add signers - first signer uses WithSigner, rest use WithPayloadSigner
-			for i, signer := range config.Signers {
-				if i == 0 {
-					opts = append(opts, overflow.WithSigner(signer))
-				} else {
-					opts = append(opts, overflow.WithPayloadSigner(signer))
-				}
-			}
-			
-			// Add arguments
-			for argName, argValue := range config.Arguments {
-				opts = append(opts, overflow.WithArg(argName, argValue))
+// RunInitTransaction runs one init transaction, either a .cdc file or a .json config file.
+// Other files are ignored. progressCallback is called like in RunInitTransactions.
+func RunInitTransaction(cdcOverflow *overflow.OverflowState, jsonOverflow *overflow.OverflowState, path string, logger *zerolog.Logger, progressCallback func(string, bool, string, string)) error {
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	
+	// Handle .json configuration files
+	if ext == ".json" {
+		baseName := strings.TrimSuffix(name, ext)
+		config, err := LoadTransactionConfig(path)
+		if err != nil {
+			logger.Error().Err(err).Str("file", name).Msg("Failed to load transaction config")
+			if progressCallback != nil {
+				progressCallback(baseName, false, err.Error(), "")
 			}
+			return err
+		}
 
-			// Execute transaction using jsonOverflow state
-			res := jsonOverflow.Tx(config.Name, opts...)
-			if res.Err != nil {
-				logger.Error().
-					Str("config", info.Name()).
-					Str("transaction", config.Name).
-					Str("error", res.Err.Error()).
-					Msg("Failed to run init transaction from config")
-				if progressCallback != nil {
-					progressCallback(baseName, false, res.Err.Error(), "")
-				}
-				return res.Err
-			}
-			
-			logger.Info().Str("config", info.Name()).Str("transaction", config.Name).Msgf("%v Ran init transaction from config", emoji.Scroll)
+		// Execute transaction using jsonOverflow state
+		res := jsonOverflow.Tx(config.Name, config.Options()...)
+		if res.Err != nil {
+			logger.Error().
+				Str("config", name).
+				Str("transaction", config.Name).
+				Str("error", res.Err.Error()).
+				Msg("Failed to run init transaction from config")
 			if progressCallback != nil {
-				progressCallback(baseName, true, "", res.Id.String())
+				progressCallback(baseName, false, res.Err.Error(), "")
 			}
-			return nil
+			return res.Err
 		}
 		
-		// Handle .cdc files (original behavior)
-		if ext == ".cdc" {
-			fileName := strings.TrimSuffix(info.Name(), ".cdc")
-			res := cdcOverflow.Tx(fileName, overflow.WithAutoSigner())
-			if res.Err != nil {
-				logger.Error().
-					Str("file", fileName).
-					Str("error", res.Err.Error()).
-					Msg("Failed to run init transaction from .cdc file")
-				if progressCallback != nil {
-					progressCallback(fileName, false, res.Err.Error(), "")
-				}
-				return res.Err
-			}
-			logger.Info().Str("file", fileName).Msgf("%v Ran init transaction", emoji.Scroll)
+		logger.Info().Str("config", name).Str("transaction", config.Name).Msgf("%v Ran init transaction from config", emoji.Scroll)
+		if progressCallback != nil {
+			progressCallback(baseName, true, "", res.Id.String())
+		}
+		return nil
+	}
+	
+	// Handle .cdc files (original behavior)
+	if ext == ".cdc" {
+		fileName := strings.TrimSuffix(name, ".cdc")
+		res := cdcOverflow.Tx(fileName, overflow.WithAutoSigner())
+		if res.Err != nil {
+			logger.Error().
+				Str("file", fileName).
+				Str("error", res.Err.Error()).
+				Msg("Failed to run init transaction from .cdc file")
 			if progressCallback != nil {
-				progressCallback(fileName, true, "", res.Id.String())
+				progressCallback(fileName, false, res.Err.Error(), "")
 			}
-			return nil
+			return res.Err
+		}
+		logger.Info().Str("file", fileName).Msgf("%v Ran init transaction", emoji.Scroll)
+		if progressCallback != nil {
+			progressCallback(fileName, true, "", res.Id.String())
 		}
-
 		return nil
-	})
-	return err
+	}
+
+	return nil
 }
 
 func AddFclContract(o *overflow.OverflowState, contract []byte) error {
@@ -127,8 +118,9 @@ transaction(code: [UInt8]) {
 }
 
 func AddFclAccounts(o *overflow.OverflowState, accounts map[string]string) error {
-	res := o.Tx(`
-    import FCL from  0xf8d6e0586b0a20c7
+	// FCL is deployed to the service account, which is not 0xf8d6e0586b0a20c7 on a forked network
+	res := o.Tx(fmt.Sprintf(`
+    import FCL from  %s
     transaction(accounts: {String:Address}) {
   prepare(acct: auth(Storage) &Account) {
 
@@ -141,7 +133,7 @@ func AddFclAccounts(o *overflow.OverflowState, accounts map[string]string) error
 
     }
   }
-}`,
+}`, o.Address(o.ServiceAccountSuffix)),
 		overflow.WithSignerServiceAccount(),
 		overflow.WithArg("accounts", accounts),
 	)
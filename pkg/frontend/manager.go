@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/bjartek/aether/pkg/events"
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/rs/zerolog"
 	"github.com/shirou/gopsutil/v3/process"
 )
@@ -70,7 +69,7 @@ func NewFrontendManager(cmd string, logger zerolog.Logger) *FrontendManager {
 	}
 }
 
-func (m *FrontendManager) Start(teaProgram *tea.Program) error {
+func (m *FrontendManager) Start(sink events.Sink) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -121,7 +120,7 @@ func (m *FrontendManager) Start(teaProgram *tea.Program) error {
 	go m.scanOutput(stderr, "frontend-err")
 
 	// Start port detection using PID
-	go m.detectPorts(teaProgram, cmd.Process.Pid)
+	go m.detectPorts(sink, cmd.Process.Pid)
 
 	// Monitor the process
 	go func() {
@@ -158,7 +157,7 @@ func (m *FrontendManager) Start(teaProgram *tea.Program) error {
 	return nil
 }
 
-func (m *FrontendManager) detectPorts(teaProgram *tea.Program, pid int) {
+func (m *FrontendManager) detectPorts(sink events.Sink, pid int) {
 	// Wait for process to start
 	time.Sleep(1 * time.Second)
 
@@ -212,9 +211,9 @@ func (m *FrontendManager) detectPorts(teaProgram *tea.Program, pid int) {
 		m.ports = ports
 		m.mu.Unlock()
 
-		if teaProgram != nil {
+		if sink != nil {
 			for _, port := range newPorts {
-				teaProgram.Send(events.FrontendPortMsg{Port: port})
+				sink.Send(events.FrontendPortMsg{Port: port})
 			}
 		}
 	}
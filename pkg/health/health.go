@@ -0,0 +1,246 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bjartek/aether/pkg/events"
+	"github.com/rs/zerolog"
+)
+
+// Names of the local services, shared with the dashboard
+const (
+	EmulatorGRPC     = "Flow Emulator (gRPC)"
+	EmulatorREST     = "Flow Emulator (REST)"
+	EmulatorAdmin    = "Flow Emulator (Admin)"
+	EmulatorDebugger = "Flow Emulator (Debugger)"
+	DevWallet        = "Dev Wallet"
+	EVMGatewayRPC    = "EVM Gateway (JSON-RPC)"
+	EVMGatewayPprof  = "EVM Gateway (Profiler)"
+)
+
+// Status of a service
+type Status string
+
+const (
+	StatusStarting Status = "starting" // Not ready yet
+	StatusReady    Status = "ready"    // The last probe succeeded
+	StatusDegraded Status = "degraded" // Was ready, but the last probe failed
+	StatusStopped  Status = "stopped"  // Stopped by aether or exited
+)
+
+// Probe checks whether a service answers, it returns nil when the service is ready
+type Probe func(ctx context.Context) error
+
+// Check is a service the monitor probes
+type Check struct {
+	Name  string
+	Port  int
+	Probe Probe
+}
+
+// StatusMsg is sent when the status of a service changes
+type StatusMsg struct {
+	Service string
+	Port    int
+	Status  Status
+	Error   string // Error of the last probe when not ready
+}
+
+// Probe intervals, services are probed often while they start and less often once they are ready
+const (
+	startingInterval = 100 * time.Millisecond
+	readyInterval    = 2 * time.Second
+	probeTimeout     = time.Second
+)
+
+// Monitor probes the local services until its context is done and reports every status change
+type Monitor struct {
+	checks []Check
+	logger zerolog.Logger
+
+	mu     sync.Mutex
+	sinks  []events.Sink
+	status map[string]StatusMsg
+	ready  map[string]chan struct{} // Closed the first time a service is ready
+}
+
+// NewMonitor creates a monitor for the given checks, all services start out as starting
+func NewMonitor(checks []Check, logger zerolog.Logger) *Monitor {
+	m := &Monitor{
+		checks: checks,
+		logger: logger,
+		status: make(map[string]StatusMsg, len(checks)),
+		ready:  make(map[string]chan struct{}, len(checks)),
+	}
+	for _, c := range checks {
+		m.status[c.Name] = StatusMsg{Service: c.Name, Port: c.Port, Status: StatusStarting}
+		m.ready[c.Name] = make(chan struct{})
+	}
+	return m
+}
+
+// Start probes every service in the background until ctx is done
+func (m *Monitor) Start(ctx context.Context) {
+	for _, c := range m.checks {
+		go m.run(ctx, c)
+	}
+}
+
+// AddSink sends all status changes to sink, starting with the current status of every service
+func (m *Monitor) AddSink(sink events.Sink) {
+	m.mu.Lock()
+	m.sinks = append(m.sinks, sink)
+	current := m.statusesLocked()
+	m.mu.Unlock()
+
+	for _, s := range current {
+		sink.Send(s)
+	}
+}
+
+// Ready returns a channel that is closed the first time the service is ready.
+// Unknown services are never ready.
+func (m *Monitor) Ready(name string) <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ch, ok := m.ready[name]; ok {
+		return ch
+	}
+	return make(chan struct{})
+}
+
+// WaitReady blocks until all the given services have been ready once or ctx is done
+func (m *Monitor) WaitReady(ctx context.Context, names ...string) error {
+	for _, name := range names {
+		select {
+		case <-m.Ready(name):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Statuses returns the current status of every service, in the order of the checks
+func (m *Monitor) Statuses() []StatusMsg {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.statusesLocked()
+}
+
+func (m *Monitor) statusesLocked() []StatusMsg {
+	statuses := make([]StatusMsg, 0, len(m.checks))
+	for _, c := range m.checks {
+		statuses = append(statuses, m.status[c.Name])
+	}
+	return statuses
+}
+
+// MarkStopped sets a service to stopped, e.g. when aether shuts it down.
+// Probes do not change the status anymore until MarkRestarting is called.
+func (m *Monitor) MarkStopped(name string) {
+	m.setStatus(name, StatusStopped, nil)
+}
+
+// MarkFailed sets a service to stopped because it could not be started again, err is shown with it
+func (m *Monitor) MarkFailed(name string, err error) {
+	m.update(name, StatusStopped, err, false)
+}
+
+// MarkRestarting sets a service back to starting after it was stopped or restarted,
+// Ready returns a new channel that is closed once the service is ready again
+func (m *Monitor) MarkRestarting(name string) {
+	m.mu.Lock()
+	if ch, ok := m.ready[name]; ok {
+		select {
+		case <-ch:
+			m.ready[name] = make(chan struct{})
+		default:
+		}
+	}
+	m.mu.Unlock()
+
+	m.update(name, StatusStarting, nil, true)
+}
+
+func (m *Monitor) run(ctx context.Context, c Check) {
+	for {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		err := c.Probe(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Stopped services are still probed slowly, they can be restarted
+		status := m.current(c.Name)
+		next := StatusReady
+		if err != nil {
+			next = StatusStarting
+			if status != StatusStarting {
+				next = StatusDegraded
+			}
+		}
+		if status != StatusStopped {
+			m.setStatus(c.Name, next, err)
+		}
+
+		interval := readyInterval
+		if status == StatusStarting && next == StatusStarting {
+			interval = startingInterval
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (m *Monitor) current(name string) Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status[name].Status
+}
+
+// setStatus records the status and reports it to the sinks if it changed
+func (m *Monitor) setStatus(name string, status Status, err error) {
+	m.update(name, status, err, false)
+}
+
+func (m *Monitor) update(name string, status Status, err error, restart bool) {
+	m.mu.Lock()
+	msg, ok := m.status[name]
+	// Only MarkRestarting revives a stopped service, a probe that was running while it was stopped must not
+	if !ok || msg.Status == status || (msg.Status == StatusStopped && !restart) {
+		m.mu.Unlock()
+		return
+	}
+	msg.Status = status
+	msg.Error = ""
+	if err != nil {
+		msg.Error = err.Error()
+	}
+	m.status[name] = msg
+	if status == StatusReady {
+		select {
+		case <-m.ready[name]:
+		default:
+			close(m.ready[name])
+		}
+	}
+	sinks := append([]events.Sink(nil), m.sinks...)
+	m.mu.Unlock()
+
+	l := m.logger.Info()
+	if status == StatusDegraded {
+		l = m.logger.Warn().Str("error", msg.Error)
+	}
+	l.Str("service", name).Str("status", string(status)).Msg("Service status changed")
+
+	for _, s := range sinks {
+		s.Send(msg)
+	}
+}
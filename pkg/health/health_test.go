@@ -0,0 +1,198 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog"
+)
+
+// recordingSink collects the status messages it receives
+type recordingSink struct {
+	mu   sync.Mutex
+	msgs []StatusMsg
+}
+
+func (s *recordingSink) Send(msg tea.Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, msg.(StatusMsg))
+}
+
+func (s *recordingSink) statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]Status, 0, len(s.msgs))
+	for _, m := range s.msgs {
+		statuses = append(statuses, m.Status)
+	}
+	return statuses
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMonitorReady(t *testing.T) {
+	var up atomic.Bool
+	probe := func(ctx context.Context) error {
+		if !up.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	m := NewMonitor([]Check{{Name: "svc", Port: 1234, Probe: probe}}, zerolog.Nop())
+	sink := &recordingSink{}
+	m.AddSink(sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+
+	select {
+	case <-m.Ready("svc"):
+		t.Fatal("service is ready before its probe succeeds")
+	case <-time.After(3 * startingInterval):
+	}
+
+	up.Store(true)
+	waitCtx, waitCancel := context.WithTimeout(ctx, 2*time.Second)
+	defer waitCancel()
+	if err := m.WaitReady(waitCtx, "svc"); err != nil {
+		t.Fatalf("WaitReady: %v", err)
+	}
+
+	waitFor(t, "ready status", func() bool { return len(sink.statuses()) == 2 })
+	got := sink.statuses()
+	if got[0] != StatusStarting || got[1] != StatusReady {
+		t.Errorf("expected starting then ready, got %v", got)
+	}
+}
+
+func TestMonitorDegradedAndStopped(t *testing.T) {
+	var up atomic.Bool
+	up.Store(true)
+	probe := func(ctx context.Context) error {
+		if !up.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	m := NewMonitor([]Check{{Name: "svc", Probe: probe}}, zerolog.Nop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+	<-m.Ready("svc")
+
+	// A service that was ready and fails its probe is degraded, not starting again
+	up.Store(false)
+	m.setStatus("svc", StatusDegraded, errors.New("connection refused"))
+	if s := m.Statuses()[0]; s.Status != StatusDegraded || s.Error != "connection refused" {
+		t.Errorf("expected degraded with error, got %+v", s)
+	}
+
+	m.MarkStopped("svc")
+	up.Store(true)
+	m.setStatus("svc", StatusReady, nil)
+	if s := m.Statuses()[0]; s.Status != StatusStopped {
+		t.Errorf("expected probes to leave a stopped service alone, got %s", s.Status)
+	}
+
+	m.MarkRestarting("svc")
+	select {
+	case <-m.Ready("svc"):
+		t.Fatal("expected a new ready channel after a restart")
+	default:
+	}
+	waitFor(t, "ready after restart", func() bool { return m.Statuses()[0].Status == StatusReady })
+	<-m.Ready("svc")
+}
+
+func TestMonitorFailed(t *testing.T) {
+	m := NewMonitor([]Check{{Name: "svc", Probe: func(ctx context.Context) error { return nil }}}, zerolog.Nop())
+	sink := &recordingSink{}
+	m.AddSink(sink)
+
+	m.MarkFailed("svc", errors.New("did not become ready"))
+	if s := m.Statuses()[0]; s.Status != StatusStopped || s.Error != "did not become ready" {
+		t.Errorf("expected stopped with the error, got %+v", s)
+	}
+	// The first message is the status the sink was added with
+	if got := sink.statuses(); got[len(got)-1] != StatusStopped {
+		t.Errorf("expected the stopped status to be sent last, got %v", got)
+	}
+}
+
+func TestWaitReadyUnknownService(t *testing.T) {
+	m := NewMonitor(nil, zerolog.Nop())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := m.WaitReady(ctx, "missing"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusNotFound
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	probe := HTTPProbe(srv.URL)
+	if err := probe(context.Background()); err != nil {
+		t.Errorf("expected a 404 to count as up, got %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := probe(context.Background()); err == nil {
+		t.Error("expected a 503 to fail the probe")
+	}
+}
+
+func TestJSONRPCProbe(t *testing.T) {
+	body := `{"jsonrpc":"2.0","id":1,"result":"0x286"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	probe := JSONRPCProbe(srv.URL)
+	if err := probe(context.Background()); err != nil {
+		t.Errorf("expected probe to succeed, got %v", err)
+	}
+
+	body = `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"not synced"}}`
+	if err := probe(context.Background()); err == nil {
+		t.Error("expected a JSON-RPC error to fail the probe")
+	}
+}
+
+func TestTCPProbe(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.Listener.Addr().String()
+
+	if err := TCPProbe(addr)(context.Background()); err != nil {
+		t.Errorf("expected probe to succeed, got %v", err)
+	}
+	srv.Close()
+	if err := TCPProbe(addr)(context.Background()); err == nil {
+		t.Error("expected probe of a closed port to fail")
+	}
+}
@@ -0,0 +1,297 @@
+// Package history is an embedded store for the transactions, events and log lines aether has shown,
+// so they can still be looked at after a restart
+package history
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// Kind is the type of an entry, every kind is its own list ordered by when the entries were added
+type Kind byte
+
+const (
+	Transactions Kind = 't'
+	Events       Kind = 'e'
+	Logs         Kind = 'l'
+	Chain        Kind = 'c' // The chain the other entries were recorded from
+)
+
+// Keys are the kind, a tag and then the sequence number of an entry or the id it was added with
+const (
+	tagEntry byte = 0
+	tagID    byte = 1
+)
+
+// Dir returns where the history of network is kept, relative to the project folder
+func Dir(network string) string {
+	return filepath.Join(".aether", "history", network)
+}
+
+// Entry is a stored value and its place in the history
+type Entry struct {
+	Seq   uint64
+	Value []byte
+}
+
+// Store keeps entries of every kind in insertion order. Entries can have an id, adding an entry
+// with an id that is already stored replaces the old one.
+type Store struct {
+	db  *pebble.DB
+	mu  sync.Mutex // Serializes writes, so the id index and sequence numbers stay consistent
+	seq uint64     // Sequence number of the newest entry, shared by all kinds
+}
+
+// Open opens or creates the store in dir. Only one process can have a store open.
+func Open(dir string) (*Store, error) {
+	db, err := pebble.Open(dir, &pebble.Options{Logger: quietLogger{}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history in %s: %w", dir, err)
+	}
+
+	s := &Store{db: db}
+	for _, kind := range []Kind{Transactions, Events, Logs, Chain} {
+		seq, err := s.newest(kind)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		s.seq = max(s.seq, seq)
+	}
+	return s, nil
+}
+
+// Close closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add stores value as the newest entry of kind and returns its place. If id is not empty an older entry
+// with that id is removed.
+func (s *Store) Add(kind Kind, id string, value []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := s.db.NewBatch()
+	defer func() { _ = batch.Close() }()
+
+	if id != "" {
+		old, ok, err := s.seqOf(kind, id)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			if err := batch.Delete(entryKey(kind, old), nil); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	seq := s.seq + 1
+	if err := batch.Set(entryKey(kind, seq), encodeEntry(id, value), nil); err != nil {
+		return 0, err
+	}
+	if id != "" {
+		if err := batch.Set(idKey(kind, id), binary.BigEndian.AppendUint64(nil, seq), nil); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return 0, err
+	}
+	s.seq = seq
+	return seq, nil
+}
+
+// Update replaces the value of the entry with id, keeping its place. It returns false if there is no such entry.
+func (s *Store) Update(kind Kind, id string, value []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq, ok, err := s.seqOf(kind, id)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, s.db.Set(entryKey(kind, seq), encodeEntry(id, value), pebble.NoSync)
+}
+
+// Get returns the value of the entry with id
+func (s *Store) Get(kind Kind, id string) ([]byte, bool, error) {
+	seq, ok, err := s.seqOf(kind, id)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	value, closer, err := s.db.Get(entryKey(kind, seq))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = closer.Close() }()
+
+	_, v, err := decodeEntry(value)
+	return v, err == nil, err
+}
+
+// Before returns up to limit entries of kind that are older than seq, or the newest ones if seq is 0.
+// They are ordered oldest first, so the Seq of the first entry is where the page before them starts.
+func (s *Store) Before(kind Kind, seq uint64, limit int) ([]Entry, error) {
+	upper := []byte{byte(kind), tagEntry + 1}
+	if seq != 0 {
+		upper = entryKey(kind, seq)
+	}
+	iter, err := s.db.NewIter(&pebble.IterOptions{LowerBound: entryKey(kind, 0), UpperBound: upper})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = iter.Close() }()
+
+	entries := make([]Entry, 0, limit)
+	for valid := iter.Last(); valid && len(entries) < limit; valid = iter.Prev() {
+		_, value, err := decodeEntry(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Seq: binary.BigEndian.Uint64(iter.Key()[2:]), Value: value})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	// Read newest first, return oldest first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// Prune removes all but the keep newest entries of kind
+func (s *Store) Prune(kind Kind, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iter, err := s.entries(kind)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = iter.Close() }()
+
+	batch := s.db.NewBatch()
+	defer func() { _ = batch.Close() }()
+
+	for valid := iter.Last(); valid; valid = iter.Prev() {
+		if keep > 0 {
+			keep--
+			continue
+		}
+		id, _, err := decodeEntry(iter.Value())
+		if err != nil {
+			return err
+		}
+		if err := batch.Delete(iter.Key(), nil); err != nil {
+			return err
+		}
+		if id != "" {
+			if err := batch.Delete(idKey(kind, id), nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if batch.Empty() {
+		return nil
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// Seq returns the place of the newest entry of any kind, entries added later are after it
+func (s *Store) Seq() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq
+}
+
+// Clear removes all entries of kind
+func (s *Store) Clear(kind Kind) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.DeleteRange([]byte{byte(kind)}, []byte{byte(kind) + 1}, pebble.Sync)
+}
+
+// newest returns the sequence number of the newest entry of kind, 0 if there are none
+func (s *Store) newest(kind Kind) (uint64, error) {
+	iter, err := s.entries(kind)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = iter.Close() }()
+
+	if !iter.Last() {
+		return 0, iter.Error()
+	}
+	return binary.BigEndian.Uint64(iter.Key()[2:]), nil
+}
+
+// entries iterates over the entries of kind, in sequence order
+func (s *Store) entries(kind Kind) (*pebble.Iterator, error) {
+	return s.db.NewIter(&pebble.IterOptions{
+		LowerBound: entryKey(kind, 0),
+		UpperBound: []byte{byte(kind), tagEntry + 1},
+	})
+}
+
+// seqOf returns the sequence number of the entry with id
+func (s *Store) seqOf(kind Kind, id string) (uint64, bool, error) {
+	value, closer, err := s.db.Get(idKey(kind, id))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = closer.Close() }()
+	return binary.BigEndian.Uint64(value), true, nil
+}
+
+func entryKey(kind Kind, seq uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte{byte(kind), tagEntry}, seq)
+}
+
+func idKey(kind Kind, id string) []byte {
+	return append([]byte{byte(kind), tagID}, id...)
+}
+
+// encodeEntry prefixes value with the id, so pruning can remove it from the index
+func encodeEntry(id string, value []byte) []byte {
+	b := binary.AppendUvarint(nil, uint64(len(id)))
+	b = append(b, id...)
+	return append(b, value...)
+}
+
+func decodeEntry(b []byte) (string, []byte, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 || uint64(len(b)-size) < n {
+		return "", nil, fmt.Errorf("corrupt history entry")
+	}
+	id := string(b[size : size+int(n)])
+	// The value is copied since pebble reuses the buffer of the iterator
+	return id, append([]byte(nil), b[size+int(n):]...), nil
+}
+
+// quietLogger drops the informational logs of pebble, by default they go to stderr and would break the TUI.
+// Log lines are stored in the history too, so they can not be sent to the aether log either.
+type quietLogger struct{}
+
+func (quietLogger) Infof(string, ...interface{}) {}
+
+func (quietLogger) Fatalf(format string, args ...interface{}) {
+	panic(fmt.Sprintf("history: "+format, args...))
+}
@@ -0,0 +1,162 @@
+package history
+
+import "testing"
+
+func openStore(t *testing.T, dir string) *Store {
+	t.Helper()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return s
+}
+
+func add(t *testing.T, s *Store, kind Kind, id, value string) uint64 {
+	t.Helper()
+	seq, err := s.Add(kind, id, []byte(value))
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return seq
+}
+
+func values(t *testing.T, s *Store, kind Kind, before uint64, limit int) []string {
+	t.Helper()
+	entries, err := s.Before(kind, before, limit)
+	if err != nil {
+		t.Fatalf("Before() error = %v", err)
+	}
+	result := make([]string, len(entries))
+	for i, e := range entries {
+		result[i] = string(e.Value)
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStorePaging(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer func() { _ = s.Close() }()
+
+	seqs := make(map[string]uint64)
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		seqs[v] = add(t, s, Logs, "", v)
+		add(t, s, Events, "", "other kind")
+	}
+
+	tests := []struct {
+		before uint64
+		limit  int
+		want   []string
+	}{
+		{0, 2, []string{"d", "e"}},
+		{seqs["d"], 2, []string{"b", "c"}},
+		{seqs["b"], 2, []string{"a"}},
+		{seqs["a"], 2, []string{}},
+		{0, 10, []string{"a", "b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		if got := values(t, s, Logs, tt.before, tt.limit); !equal(got, tt.want) {
+			t.Errorf("Before(%d, %d) = %v, want %v", tt.before, tt.limit, got, tt.want)
+		}
+	}
+
+	entries, err := s.Before(Logs, 0, 2)
+	if err != nil || entries[0].Seq != seqs["d"] {
+		t.Errorf("Before() first entry = %+v, want seq %d", entries, seqs["d"])
+	}
+}
+
+func TestStoreIDs(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer func() { _ = s.Close() }()
+
+	first := add(t, s, Transactions, "1", "one")
+	add(t, s, Transactions, "2", "two")
+	add(t, s, Transactions, "3", "three")
+
+	// Adding an id again moves it to the newest entry
+	if again := add(t, s, Transactions, "1", "one again"); again <= first {
+		t.Errorf("Add() of an existing id = %d, want a newer place than %d", again, first)
+	}
+	if got, want := values(t, s, Transactions, 0, 10), []string{"two", "three", "one again"}; !equal(got, want) {
+		t.Errorf("Before() = %v, want %v", got, want)
+	}
+
+	ok, err := s.Update(Transactions, "2", []byte("two updated"))
+	if err != nil || !ok {
+		t.Fatalf("Update() = %v, %v", ok, err)
+	}
+	if ok, _ := s.Update(Transactions, "4", []byte("missing")); ok {
+		t.Error("Update() of a missing id should return false")
+	}
+	if got, want := values(t, s, Transactions, 0, 10), []string{"two updated", "three", "one again"}; !equal(got, want) {
+		t.Errorf("Before() after update = %v, want %v", got, want)
+	}
+
+	value, ok, err := s.Get(Transactions, "3")
+	if err != nil || !ok || string(value) != "three" {
+		t.Errorf("Get() = %q, %v, %v", value, ok, err)
+	}
+}
+
+func TestStorePruneAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		add(t, s, Transactions, id, "tx"+id)
+	}
+	if err := s.Prune(Transactions, 2); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if _, ok, _ := s.Get(Transactions, "1"); ok {
+		t.Error("pruned entry should be gone from the id index")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// New entries continue after the ones already stored
+	s = openStore(t, dir)
+	defer func() { _ = s.Close() }()
+	add(t, s, Transactions, "5", "tx5")
+	if got, want := values(t, s, Transactions, 0, 10), []string{"tx3", "tx4", "tx5"}; !equal(got, want) {
+		t.Errorf("Before() after reopen = %v, want %v", got, want)
+	}
+}
+
+func TestStoreClear(t *testing.T) {
+	s := openStore(t, t.TempDir())
+	defer func() { _ = s.Close() }()
+
+	add(t, s, Transactions, "1", "tx1")
+	last := add(t, s, Logs, "", "line")
+	if err := s.Clear(Transactions); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if got := values(t, s, Transactions, 0, 10); len(got) != 0 {
+		t.Errorf("Before() after Clear() = %v, want nothing", got)
+	}
+	if _, ok, _ := s.Get(Transactions, "1"); ok {
+		t.Error("cleared entry should be gone from the id index")
+	}
+	if got, want := values(t, s, Logs, 0, 10), []string{"line"}; !equal(got, want) {
+		t.Errorf("Before() of another kind = %v, want %v", got, want)
+	}
+
+	// Places keep going up, so views do not mix up old and new entries
+	if seq := add(t, s, Transactions, "1", "tx1 again"); seq <= last || s.Seq() != seq {
+		t.Errorf("Add() after Clear() = %d, Seq() = %d, want after %d", seq, s.Seq(), last)
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 // NewLogger creates a zerolog logger that outputs to a channel-buffered writer.
-// Call AttachProgram on the returned writer after creating the Tea program.
+// Call AttachSink on the returned writer after creating the Tea program (or headless sink).
 func NewLogger(bufferSize int) (zerolog.Logger, *LogWriter) {
 	// Create the TUI writer with channel buffering
 	tuiWriter := NewLogWriter(bufferSize)
@@ -30,7 +30,7 @@ func NewLogger(bufferSize int) (zerolog.Logger, *LogWriter) {
 }
 
 // NewLoggerWithFile creates a zerolog logger that outputs to both a file and a channel-buffered writer.
-// Call AttachProgram on the returned writer after creating the Tea program.
+// Call AttachSink on the returned writer after creating the Tea program (or headless sink).
 func NewLoggerWithFile(logFilePath string, bufferSize int) (zerolog.Logger, *LogWriter, error) {
 	// Create the TUI writer with channel buffering
 	tuiWriter := NewLogWriter(bufferSize)
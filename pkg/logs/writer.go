@@ -6,19 +6,20 @@ import (
 	"os"
 	"sync"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/bjartek/aether/pkg/events"
 )
 
 // LogLineMsg is sent when a new log line is available
 type LogLineMsg struct {
-	Line string
-	Err  error
+	Line       string
+	Err        error
+	HistorySeq uint64 // Place in the history, 0 when it is not stored
 }
 
-// LogWriter is a custom io.Writer that sends log lines to a channel or Bubble Tea program.
-// It buffers logs to a channel before the Tea program is ready, then drains them when attached.
+// LogWriter is a custom io.Writer that sends log lines to a channel or sink.
+// It buffers logs to a channel before the sink is ready, then drains them when attached.
 type LogWriter struct {
-	program *tea.Program
+	sink    events.Sink
 	buffer  bytes.Buffer
 	mu      sync.Mutex
 	logChan chan string
@@ -26,7 +27,7 @@ type LogWriter struct {
 }
 
 // NewLogWriter creates a new log writer that sends lines to a buffered channel.
-// The channel will buffer logs until AttachProgram is called.
+// The channel will buffer logs until AttachSink is called.
 func NewLogWriter(bufferSize int) *LogWriter {
 	return &LogWriter{
 		logChan: make(chan string, bufferSize),
@@ -40,26 +41,26 @@ func (w *LogWriter) SetLogFile(file *os.File) {
 	w.logFile = file
 }
 
-// AttachProgram attaches a Bubble Tea program and drains any buffered logs.
-// This should be called after the Tea program is created.
-func (w *LogWriter) AttachProgram(program *tea.Program) {
+// AttachSink attaches a sink (usually the Bubble Tea program) and drains any buffered logs.
+// This should be called after the sink is created.
+func (w *LogWriter) AttachSink(sink events.Sink) {
 	w.mu.Lock()
-	w.program = program
+	w.sink = sink
 	w.mu.Unlock()
 
 	// Drain buffered logs from channel
 	go func() {
 		for line := range w.logChan {
 			w.mu.Lock()
-			if w.program != nil {
-				w.program.Send(LogLineMsg{Line: line})
+			if w.sink != nil {
+				w.sink.Send(LogLineMsg{Line: line})
 			}
 			w.mu.Unlock()
 		}
 	}()
 }
 
-// Write implements io.Writer and sends complete lines to the channel or program.
+// Write implements io.Writer and sends complete lines to the channel or sink.
 func (w *LogWriter) Write(p []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -76,7 +77,7 @@ func (w *LogWriter) Write(p []byte) (n int, err error) {
 
 	for scanner.Scan() {
 		line := scanner.Text() + "\n"
-		// Send to channel (non-blocking if program not attached yet)
+		// Send to channel (non-blocking if sink not attached yet)
 		select {
 		case w.logChan <- line:
 		default:
@@ -0,0 +1,292 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bjartek/overflow/v2"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// Executor runs transactions and scripts by name, *overflow.OverflowState satisfies it
+type Executor interface {
+	Tx(filename string, opts ...overflow.OverflowInteractionOption) *overflow.OverflowResult
+	Script(filename string, opts ...overflow.OverflowInteractionOption) *overflow.OverflowScriptResult
+}
+
+// StepResult is the outcome of a single step
+type StepResult struct {
+	Index         int           `json:"index"`
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	Passed        bool          `json:"passed"`
+	Failures      []string      `json:"failures,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	TransactionID string        `json:"transactionId,omitempty"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// Result is the outcome of running a scenario
+type Result struct {
+	Name     string        `json:"name"`
+	File     string        `json:"file"`
+	Steps    []StepResult  `json:"steps"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Passed reports whether every step passed
+func (r Result) Passed() bool {
+	for _, step := range r.Steps {
+		if !step.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Run executes all steps of a scenario in order and checks their expectations.
+// A failing step does not stop the scenario, later steps are still run and reported.
+func Run(o Executor, s *Scenario) Result {
+	start := time.Now()
+	result := Result{
+		Name: s.Name,
+		File: s.File,
+	}
+
+	for i, step := range s.Steps {
+		result.Steps = append(result.Steps, runStep(o, i+1, step))
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+func runStep(o Executor, index int, step Step) StepResult {
+	start := time.Now()
+	config := step.Config()
+	sr := StepResult{
+		Index: index,
+		Name:  step.DisplayName(),
+		Type:  "transaction",
+	}
+
+	var err error
+	if step.IsScript() {
+		sr.Type = "script"
+		res := o.Script(config.Name, config.Options()...)
+		err = res.Err
+		if err == nil && step.Expect.Result != nil && !valuesEqual(step.Expect.Result, res.Output) {
+			sr.Failures = append(sr.Failures, fmt.Sprintf("expected result %s, got %s", format(step.Expect.Result), format(res.Output)))
+		}
+	} else {
+		res := o.Tx(config.Name, config.Options()...)
+		err = res.Err
+		if res.Id != flow.EmptyID {
+			sr.TransactionID = res.Id.String()
+		}
+		if err == nil {
+			sr.Failures = append(sr.Failures, checkEvents(step.Expect.Events, res.Events)...)
+		}
+	}
+
+	if err != nil {
+		sr.Error = err.Error()
+	}
+	sr.Failures = append(checkOutcome(step.Expect, err), sr.Failures...)
+	sr.Passed = len(sr.Failures) == 0
+	sr.Duration = time.Since(start)
+	return sr
+}
+
+// checkOutcome compares the error returned by a step with the expected success/failure
+func checkOutcome(expect Expectation, err error) []string {
+	if expect.ExpectSuccess() {
+		if err != nil {
+			return []string{fmt.Sprintf("expected success, got error: %v", err)}
+		}
+		return nil
+	}
+
+	if err == nil {
+		return []string{"expected failure, but step succeeded"}
+	}
+	if expect.Error != "" && !strings.Contains(err.Error(), expect.Error) {
+		return []string{fmt.Sprintf("expected error containing %q, got: %v", expect.Error, err)}
+	}
+	return nil
+}
+
+// checkEvents verifies that every expected event was emitted with the given field values
+func checkEvents(expected []ExpectedEvent, emitted overflow.OverflowEvents) []string {
+	var failures []string
+	for _, exp := range expected {
+		candidates := eventsOfType(emitted, exp.Type)
+		if len(candidates) == 0 {
+			failures = append(failures, fmt.Sprintf("expected event %s, but it was not emitted", exp.Type))
+			continue
+		}
+
+		matched := false
+		for _, event := range candidates {
+			if fieldsMatch(exp.Fields, event.Fields) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			var got []string
+			for _, event := range candidates {
+				got = append(got, format(event.Fields))
+			}
+			failures = append(failures, fmt.Sprintf("expected event %s with fields %s, got %s", exp.Type, format(exp.Fields), strings.Join(got, ", ")))
+		}
+	}
+	return failures
+}
+
+// eventsOfType returns the events whose name equals eventType or ends with .eventType
+func eventsOfType(emitted overflow.OverflowEvents, eventType string) []overflow.OverflowEvent {
+	names := make([]string, 0, len(emitted))
+	for name := range emitted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var events []overflow.OverflowEvent
+	for _, name := range names {
+		if name == eventType || strings.HasSuffix(name, "."+eventType) {
+			events = append(events, emitted[name]...)
+		}
+	}
+	return events
+}
+
+func fieldsMatch(expected map[string]interface{}, actual map[string]interface{}) bool {
+	for key, value := range expected {
+		got, ok := actual[key]
+		if !ok || !valuesEqual(value, got) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares an expected value from a scenario file with a value decoded by overflow.
+// Both are normalized through JSON so that e.g. int and uint64 or yaml and json maps compare equal.
+// Numbers are compared by value, also when one side is a number in a string (so "10.0" matches a UFix64 of 10.0),
+// but values of other types never match, e.g. "true" does not match true.
+func valuesEqual(expected interface{}, actual interface{}) bool {
+	e, eErr := normalize(expected)
+	a, aErr := normalize(actual)
+	if eErr != nil || aErr != nil {
+		return false
+	}
+	return normalizedEqual(e, a)
+}
+
+func normalizedEqual(expected interface{}, actual interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for key, value := range e {
+			if got, ok := a[key]; !ok || !normalizedEqual(value, got) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !normalizedEqual(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// A string is only read as a number when the other side is one, e.g. a UFix64 against a yaml float
+	_, eNumber := expected.(json.Number)
+	_, aNumber := actual.(json.Number)
+	if eNumber || aNumber {
+		e, eOk := numberValue(expected)
+		a, aOk := numberValue(actual)
+		return eOk && aOk && e.Cmp(a) == 0
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// numberValue returns the value of a normalized number or of a string with a decimal number in it
+func numberValue(value interface{}) (*big.Rat, bool) {
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		if !decimalPattern.MatchString(v) {
+			return nil, false
+		}
+		text = v
+	default:
+		return nil, false
+	}
+	return new(big.Rat).SetString(text)
+}
+
+// normalize turns value into what it decodes to as JSON, numbers are kept as json.Number so large
+// integers keep their precision
+func normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var out interface{}
+	if err := decoder.Decode(&out); err != nil {
+		return value, err
+	}
+	return out, nil
+}
+
+func format(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// Print writes a human readable pass/fail report
+func (r Result) Print(w io.Writer) {
+	status := "PASS"
+	if !r.Passed() {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w, "%s %s (%s)\n", status, r.Name, r.Duration.Round(time.Millisecond))
+	for _, step := range r.Steps {
+		mark := "✓"
+		if !step.Passed {
+			mark = "✗"
+		}
+		fmt.Fprintf(w, "  %s %d. %s %s\n", mark, step.Index, step.Type, step.Name)
+		for _, failure := range step.Failures {
+			fmt.Fprintf(w, "      - %s\n", failure)
+		}
+	}
+}
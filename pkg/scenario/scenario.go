@@ -0,0 +1,173 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bjartek/aether/pkg/flow"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFolder is the folder scenarios are read from, next to the aether init transactions folder
+const DefaultFolder = "scenarios"
+
+// Scenario is an ordered list of steps run against the emulator
+type Scenario struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Steps       []Step `yaml:"steps" json:"steps"`
+
+	// File is the path the scenario was loaded from
+	File string `yaml:"-" json:"-"`
+}
+
+// Step is a single transaction or script with optional expectations.
+// Signers and arguments work like in flow.TransactionConfig
+type Step struct {
+	Name        string                 `yaml:"name,omitempty" json:"name,omitempty"`
+	Transaction string                 `yaml:"transaction,omitempty" json:"transaction,omitempty"`
+	Script      string                 `yaml:"script,omitempty" json:"script,omitempty"`
+	Signers     []string               `yaml:"signers,omitempty" json:"signers,omitempty"`
+	Arguments   map[string]interface{} `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+	Expect      Expectation            `yaml:"expect,omitempty" json:"expect"`
+}
+
+// Expectation describes the expected outcome of a step
+type Expectation struct {
+	// Success defaults to true, unless Error is set
+	Success *bool `yaml:"success,omitempty" json:"success,omitempty"`
+	// Error is a substring the error message must contain, implies failure
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+	// Events that must be emitted by a transaction
+	Events []ExpectedEvent `yaml:"events,omitempty" json:"events,omitempty"`
+	// Result is the expected return value of a script
+	Result interface{} `yaml:"result,omitempty" json:"result,omitempty"`
+}
+
+// ExpectedEvent matches an emitted event.
+// Type can be the full identifier (A.f8d6e0586b0a20c7.Counter.CounterIncremented) or a suffix of it (Counter.CounterIncremented).
+// Fields only need to contain the listed keys.
+type ExpectedEvent struct {
+	Type   string                 `yaml:"type" json:"type"`
+	Fields map[string]interface{} `yaml:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// IsScript reports whether the step runs a script
+func (s Step) IsScript() bool {
+	return s.Script != ""
+}
+
+// Target returns the name of the transaction or script to run
+func (s Step) Target() string {
+	if s.IsScript() {
+		return s.Script
+	}
+	return s.Transaction
+}
+
+// DisplayName returns the step name or falls back to the target
+func (s Step) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Target()
+}
+
+// Config converts the step into a transaction config so it can be run like the init transactions
+func (s Step) Config() flow.TransactionConfig {
+	return flow.TransactionConfig{
+		Name:      s.Target(),
+		Signers:   s.Signers,
+		Arguments: s.Arguments,
+	}
+}
+
+// ExpectSuccess reports whether the step is expected to succeed
+func (e Expectation) ExpectSuccess() bool {
+	if e.Success != nil {
+		return *e.Success
+	}
+	return e.Error == ""
+}
+
+// Load reads a scenario from a .yaml, .yml or .json file
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Scenario
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &s)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &s)
+	default:
+		return nil, fmt.Errorf("unsupported scenario file %q, expected .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %q: %w", path, err)
+	}
+
+	s.File = path
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %q: %w", path, err)
+	}
+	return &s, nil
+}
+
+// LoadDir reads all scenarios in a folder in alphabetical order, like init transactions
+func LoadDir(dir string) ([]*Scenario, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	scenarios := make([]*Scenario, 0, len(names))
+	for _, name := range names {
+		s, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+func (s *Scenario) validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+	for i, step := range s.Steps {
+		if (step.Transaction == "") == (step.Script == "") {
+			return fmt.Errorf("step %d: exactly one of transaction or script must be set", i+1)
+		}
+		if step.IsScript() && len(step.Expect.Events) > 0 {
+			return fmt.Errorf("step %d: scripts do not emit events", i+1)
+		}
+		if !step.IsScript() && step.Expect.Result != nil {
+			return fmt.Errorf("step %d: transactions do not return a result", i+1)
+		}
+	}
+	return nil
+}
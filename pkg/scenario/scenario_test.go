@@ -0,0 +1,185 @@
+package scenario
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bjartek/overflow/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const counterScenario = `
+name: counter
+steps:
+  - name: increment as bob
+    transaction: IncrementCounter
+    signers: [bob]
+    expect:
+      events:
+        - type: Counter.CounterIncremented
+          fields:
+            newCount: 1
+  - script: GetCounter
+    expect:
+      result: 1
+  - transaction: IncrementCounter
+    signers: [nobody]
+    expect:
+      error: could not find account
+`
+
+// fakeExecutor returns canned results keyed by transaction/script name
+type fakeExecutor struct {
+	txs     map[string]*overflow.OverflowResult
+	scripts map[string]*overflow.OverflowScriptResult
+	calls   []string
+}
+
+func (f *fakeExecutor) Tx(name string, opts ...overflow.OverflowInteractionOption) *overflow.OverflowResult {
+	f.calls = append(f.calls, name)
+	return f.txs[name]
+}
+
+func (f *fakeExecutor) Script(name string, opts ...overflow.OverflowInteractionOption) *overflow.OverflowScriptResult {
+	f.calls = append(f.calls, name)
+	return f.scripts[name]
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "1_counter.yaml", counterScenario)
+	createFile(t, dir, "2_script.json", `{"steps":[{"script":"GetCounter","expect":{"result":1}}]}`)
+	createFile(t, dir, "README.md", "# ignored")
+
+	scenarios, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, scenarios, 2)
+
+	counter := scenarios[0]
+	assert.Equal(t, "counter", counter.Name)
+	require.Len(t, counter.Steps, 3)
+	assert.Equal(t, []string{"bob"}, counter.Steps[0].Signers)
+	assert.True(t, counter.Steps[0].Expect.ExpectSuccess())
+	assert.False(t, counter.Steps[2].Expect.ExpectSuccess())
+	assert.True(t, counter.Steps[1].IsScript())
+
+	// Name falls back to the file name
+	assert.Equal(t, "2_script", scenarios[1].Name)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "both.yaml", "steps:\n  - transaction: a\n    script: b\n")
+	createFile(t, dir, "empty.yaml", "name: empty\n")
+
+	_, err := Load(filepath.Join(dir, "both.yaml"))
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(dir, "empty.yaml"))
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "counter.yaml", counterScenario)
+	s, err := Load(filepath.Join(dir, "counter.yaml"))
+	require.NoError(t, err)
+
+	executor := &fakeExecutor{
+		txs: map[string]*overflow.OverflowResult{
+			"IncrementCounter": {
+				Events: overflow.OverflowEvents{
+					"A.f8d6e0586b0a20c7.Counter.CounterIncremented": {
+						{Fields: map[string]interface{}{"newCount": uint64(1)}},
+					},
+				},
+			},
+		},
+		scripts: map[string]*overflow.OverflowScriptResult{
+			"GetCounter": {Output: uint64(1)},
+		},
+	}
+
+	result := Run(executor, s)
+	require.Len(t, result.Steps, 3)
+	assert.True(t, result.Steps[0].Passed, result.Steps[0].Failures)
+	assert.True(t, result.Steps[1].Passed, result.Steps[1].Failures)
+
+	// The last step expected an error but the fake succeeded
+	assert.False(t, result.Steps[2].Passed)
+	assert.False(t, result.Passed())
+	assert.Equal(t, []string{"IncrementCounter", "GetCounter", "IncrementCounter"}, executor.calls)
+}
+
+func TestCheckOutcome(t *testing.T) {
+	fail := false
+	tests := []struct {
+		name    string
+		expect  Expectation
+		err     error
+		wantErr bool
+	}{
+		{name: "success", expect: Expectation{}, err: nil, wantErr: false},
+		{name: "unexpected error", expect: Expectation{}, err: errors.New("boom"), wantErr: true},
+		{name: "expected failure", expect: Expectation{Success: &fail}, err: errors.New("boom"), wantErr: false},
+		{name: "error substring", expect: Expectation{Error: "panic"}, err: errors.New("cadence panic: nope"), wantErr: false},
+		{name: "wrong error", expect: Expectation{Error: "panic"}, err: errors.New("boom"), wantErr: true},
+		{name: "unexpected success", expect: Expectation{Error: "panic"}, err: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkOutcome(tt.expect, tt.err)
+			assert.Equal(t, tt.wantErr, len(failures) > 0, failures)
+		})
+	}
+}
+
+func TestCheckEvents(t *testing.T) {
+	emitted := overflow.OverflowEvents{
+		"A.0ae53cb6e3f42a79.FlowToken.TokensDeposited": {
+			{Fields: map[string]interface{}{"amount": 10.0, "to": "0x179b6b1cb6755e31"}},
+		},
+	}
+
+	assert.Empty(t, checkEvents([]ExpectedEvent{{Type: "FlowToken.TokensDeposited"}}, emitted))
+	assert.Empty(t, checkEvents([]ExpectedEvent{{Type: "FlowToken.TokensDeposited", Fields: map[string]interface{}{"amount": 10}}}, emitted))
+	assert.Len(t, checkEvents([]ExpectedEvent{{Type: "FlowToken.TokensDeposited", Fields: map[string]interface{}{"amount": 11}}}, emitted), 1)
+	assert.Len(t, checkEvents([]ExpectedEvent{{Type: "FlowToken.TokensWithdrawn"}}, emitted), 1)
+}
+
+func TestValuesEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		want     bool
+	}{
+		{"int and uint64", 10, uint64(10), true},
+		{"yaml float and UFix64 string", 10.0, "10.00000000", true},
+		{"UFix64 string and yaml float", "10.5", 10.5, true},
+		{"large integers", uint64(18446744073709551615), "18446744073709551615", true},
+		{"different numbers", 10, 11, false},
+		{"string and bool", "true", true, false},
+		{"string and array", "[a b]", []interface{}{"a", "b"}, false},
+		{"number and non decimal string", 1, "0x1", false},
+		{"strings", "0x01", "0x01", true},
+		{"maps", map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1.0}, true},
+		{"map with extra key", map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1, "b": 2}, false},
+		{"arrays", []interface{}{1, "x"}, []interface{}{uint8(1), "x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, valuesEqual(tt.expected, tt.actual))
+		})
+	}
+}
+
+func createFile(t *testing.T, dir, filename, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644))
+}
@@ -339,6 +339,14 @@ func (sv *SplitViewModel) GetCursor() int {
 	return sv.table.Cursor()
 }
 
+// SetCursor selects the row at the given index
+func (m *SplitViewModel) SetCursor(index int) {
+	if index < 0 || index >= len(m.rows) {
+		return
+	}
+	m.table.SetCursor(index)
+}
+
 // IsFullscreen returns whether the view is in fullscreen mode
 func (m *SplitViewModel) IsFullscreen() bool {
 	return m.fullDetailMode
@@ -195,6 +195,21 @@ func TestGetRows(t *testing.T) {
 	}
 }
 
+func TestSetCursor(t *testing.T) {
+	m := NewSplitView(testColumns, WithRows(testRows))
+
+	m.SetCursor(2)
+	if m.GetCursor() != 2 {
+		t.Errorf("expected cursor 2, got %d", m.GetCursor())
+	}
+
+	// Out of range indexes are ignored
+	m.SetCursor(len(testRows))
+	if m.GetCursor() != 2 {
+		t.Errorf("expected cursor to stay at 2, got %d", m.GetCursor())
+	}
+}
+
 func TestInit(t *testing.T) {
 	m := NewSplitView(testColumns)
 	cmd := m.Init()
@@ -218,6 +218,15 @@ func (m TabbedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case SwitchTabMsg:
+		for i, tab := range m.tabs {
+			if tab.Name() == msg.Name {
+				m.activeTab = i
+				break
+			}
+		}
+		return m, nil
+
 	default:
 		// Broadcast all other messages to all tabs
 		// Each tab decides whether to handle the message
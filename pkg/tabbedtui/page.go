@@ -14,6 +14,16 @@ func InputHandled() tea.Cmd {
 	return func() tea.Msg { return InputHandledMsg{} }
 }
 
+// SwitchTabMsg makes the tab with the given name the active tab
+type SwitchTabMsg struct {
+	Name string
+}
+
+// SwitchTab returns a command that switches to the tab with the given name
+func SwitchTab(name string) tea.Cmd {
+	return func() tea.Msg { return SwitchTabMsg{Name: name} }
+}
+
 // TabbedModelPage defines the interface for models that can be used as tabs
 type TabbedModelPage interface {
 	tea.Model
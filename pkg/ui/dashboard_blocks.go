@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Inputs of the block controls
+const (
+	promptBlocks = "blocks"
+	promptTime   = "time"
+)
+
+// blockControls is the dashboard state to commit blocks and move the emulator forward in time
+type blockControls struct {
+	busy   bool
+	prompt string          // Active input, promptBlocks or promptTime, empty when none
+	input  textinput.Model // Input for the number of blocks or the duration
+	status string          // Result of the last action
+	err    string          // Error from the last action
+}
+
+// blocksCommittedMsg is sent when blocks have been committed or time was advanced
+type blocksCommittedMsg struct {
+	action string // e.g. "Committed 5 block(s)"
+	block  aether.CommittedBlock
+	err    error
+}
+
+func newBlockControls() blockControls {
+	input := textinput.New()
+	input.CharLimit = 10
+	input.Width = 12
+	return blockControls{input: input}
+}
+
+// manualBlocksEnabled reports whether blocks are committed on demand from the dashboard
+func (dv *DashboardView) manualBlocksEnabled() bool {
+	return dv.aetherServer != nil && dv.aetherServer.ManualBlocks()
+}
+
+// timeTravelEnabled reports whether the emulator can be moved forward in time and skip blocks
+func (dv *DashboardView) timeTravelEnabled() bool {
+	return dv.aetherServer != nil && dv.aetherServer.TimeTravelEnabled()
+}
+
+// runBlockAction runs an action that commits blocks in the background
+func (dv *DashboardView) runBlockAction(action string, fn func() (aether.CommittedBlock, error)) tea.Cmd {
+	b := &dv.blocks
+	b.busy = true
+	b.status = ""
+	b.err = ""
+	return func() tea.Msg {
+		block, err := fn()
+		return blocksCommittedMsg{action: action, block: block, err: err}
+	}
+}
+
+// commitBlocks commits count blocks in the background
+func (dv *DashboardView) commitBlocks(count int) tea.Cmd {
+	server := dv.aetherServer
+	return dv.runBlockAction(fmt.Sprintf("Committed %d block(s)", count), func() (aether.CommittedBlock, error) {
+		return server.CommitBlocks(count)
+	})
+}
+
+// advanceTime moves the emulator time forward in the background
+func (dv *DashboardView) advanceTime(d time.Duration) tea.Cmd {
+	server := dv.aetherServer
+	return dv.runBlockAction(fmt.Sprintf("Advanced time by %s", d), func() (aether.CommittedBlock, error) {
+		return server.AdvanceTime(d)
+	})
+}
+
+// blocksCommitted shows the result of committing blocks
+func (dv *DashboardView) blocksCommitted(msg blocksCommittedMsg) {
+	b := &dv.blocks
+	b.busy = false
+	if msg.err != nil {
+		dv.logger.Error().Err(msg.err).Str("action", msg.action).Msg("Block action failed")
+		b.err = msg.err.Error()
+		return
+	}
+	b.status = fmt.Sprintf("✓ %s, height %d", msg.action, msg.block.Height)
+}
+
+// promptBlockInput shows the input for the number of blocks or the duration to advance
+func (dv *DashboardView) promptBlockInput(prompt string) tea.Cmd {
+	b := &dv.blocks
+	b.prompt = prompt
+	b.err = ""
+	if prompt == promptTime {
+		b.input.Placeholder = "e.g. 1h or 7d"
+	} else {
+		b.input.Placeholder = "blocks"
+	}
+	return b.input.Focus()
+}
+
+// updateBlockInput handles keys while the block count or duration input is active
+func (dv *DashboardView) updateBlockInput(msg tea.KeyMsg) tea.Cmd {
+	b := &dv.blocks
+	switch msg.Type {
+	case tea.KeyEnter:
+		value := strings.TrimSpace(b.input.Value())
+		if b.prompt == promptTime {
+			d, err := aether.ParseTravelDuration(value)
+			if err != nil {
+				b.err = err.Error()
+				return nil
+			}
+			dv.closeBlockInput()
+			return dv.advanceTime(d)
+		}
+
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 {
+			b.err = fmt.Sprintf("invalid block count %q", value)
+			return nil
+		}
+		dv.closeBlockInput()
+		return dv.commitBlocks(count)
+	case tea.KeyEsc:
+		dv.closeBlockInput()
+		return nil
+	default:
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		return cmd
+	}
+}
+
+func (dv *DashboardView) closeBlockInput() {
+	dv.blocks.prompt = ""
+	dv.blocks.input.SetValue("")
+	dv.blocks.input.Blur()
+}
+
+// renderEmulatedTime renders the timestamp of the latest block, with how far it is ahead of the wall clock
+func (dv *DashboardView) renderEmulatedTime() string {
+	if dv.latestBlockTime.IsZero() {
+		return ""
+	}
+	line := labelStyle.Render("Time: ") + valueStyle.Render(dv.latestBlockTime.UTC().Format(dv.timestampFormat))
+	// Blocks are a bit behind the wall clock when they are not produced all the time, only show real time travel
+	if ahead := time.Until(dv.latestBlockTime); ahead > time.Minute {
+		line += "\n" + dimStyle.Render(fmt.Sprintf("%s ahead of the wall clock", ahead.Round(time.Minute)))
+	}
+	return line
+}
+
+// renderBlockControls renders the active input and the result of the last block action
+func (dv *DashboardView) renderBlockControls() string {
+	b := dv.blocks
+	var content strings.Builder
+	switch b.prompt {
+	case promptBlocks:
+		content.WriteString("\n\n" + labelStyle.Render("Commit blocks: ") + b.input.View())
+	case promptTime:
+		content.WriteString("\n\n" + labelStyle.Render("Advance time by: ") + b.input.View())
+	}
+	switch {
+	case b.busy:
+		content.WriteString("\n\n" + dimStyle.Render("Committing..."))
+	case b.err != "":
+		content.WriteString("\n\n" + lipgloss.NewStyle().Foreground(errorColor).Render("✗ "+b.err))
+	case b.status != "":
+		content.WriteString("\n\n" + lipgloss.NewStyle().Foreground(successColor).Render(b.status))
+	}
+	return content.String()
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/bjartek/aether/pkg/aether"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// coverageWrittenMsg is sent when a coverage report has been written
+type coverageWrittenMsg struct {
+	result aether.CoverageResult
+	err    error
+}
+
+// coverageEnabled reports whether the emulator collects coverage that can be written from the dashboard
+func (dv *DashboardView) coverageEnabled() bool {
+	return dv.aetherServer != nil && dv.aetherServer.CoverageEnabled()
+}
+
+// writeCoverage writes the coverage report in the background
+func (dv *DashboardView) writeCoverage() tea.Cmd {
+	dv.coverageStatus = "Writing coverage report..."
+	dv.coverageErr = ""
+	server := dv.aetherServer
+	return func() tea.Msg {
+		result, err := server.WriteCoverageReport()
+		return coverageWrittenMsg{result: result, err: err}
+	}
+}
+
+// coverageWritten shows the result of writing the coverage report
+func (dv *DashboardView) coverageWritten(msg coverageWrittenMsg) {
+	if msg.err != nil {
+		dv.logger.Error().Err(msg.err).Msg("Failed to write coverage report")
+		dv.coverageStatus = ""
+		dv.coverageErr = msg.err.Error()
+		return
+	}
+	dv.coverageStatus = fmt.Sprintf("✓ Coverage %s of %d contracts written to %s", msg.result.Percentage, msg.result.Contracts, msg.result.LCOVPath)
+}
+
+// renderCoverageStatus renders the result of the last coverage report, empty if none was written
+func (dv *DashboardView) renderCoverageStatus() string {
+	switch {
+	case dv.coverageErr != "":
+		return lipgloss.NewStyle().Foreground(errorColor).Render("✗ Coverage: " + dv.coverageErr)
+	case dv.coverageStatus != "":
+		return lipgloss.NewStyle().Foreground(successColor).Render(dv.coverageStatus)
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Limits for showing contract reloads in the init transactions box
+const (
+	maxContractReloads  = 5
+	maxReloadErrorLines = 8
+)
+
+// contractReloaded keeps the latest contract reloads, newest last
+func (dv *DashboardView) contractReloaded(msg aether.ContractReloadMsg) {
+	dv.contractReloads = append(dv.contractReloads, msg)
+	if len(dv.contractReloads) > maxContractReloads {
+		dv.contractReloads = dv.contractReloads[len(dv.contractReloads)-maxContractReloads:]
+	}
+}
+
+// renderContractReloads renders the latest contract reloads with the checker or update error of failed ones,
+// empty if no contract was reloaded
+func (dv *DashboardView) renderContractReloads() string {
+	if len(dv.contractReloads) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("\n\n" + labelStyle.Render("Contract reloads:") + "\n")
+	for _, r := range dv.contractReloads {
+		name := r.Contract + " → " + r.Account
+		if !r.Success {
+			content.WriteString(lipgloss.NewStyle().Foreground(errorColor).Render("✗") + " " + valueStyle.Render(name) + "\n")
+			lines := strings.Split(strings.TrimSpace(r.Error), "\n")
+			if len(lines) > maxReloadErrorLines {
+				lines = append(lines[:maxReloadErrorLines], "... see the Logs tab")
+			}
+			for _, line := range lines {
+				content.WriteString(dimStyle.Render("  "+line) + "\n")
+			}
+			continue
+		}
+
+		line := lipgloss.NewStyle().Foreground(successColor).Render("✓") + " " + valueStyle.Render(name)
+		if r.Redeployed {
+			line += dimStyle.Render(" (removed and deployed again)")
+		}
+		content.WriteString(line + "\n")
+	}
+	return content.String()
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// restartEntry is one choice in the restart panel
+type restartEntry struct {
+	service  string
+	redeploy bool // Deploy contracts and run init transactions after an emulator restart
+	label    string
+}
+
+// restartPanel is the dashboard panel to restart a single local service
+type restartPanel struct {
+	open     bool
+	entries  []restartEntry
+	selected int
+	busy     string // Restart in progress, e.g. "Restarting emulator..."
+	status   string // Result of the last restart
+	err      string // Error from the last restart
+}
+
+// restartResultMsg is sent when a restart has finished
+type restartResultMsg struct {
+	entry restartEntry
+	err   error
+}
+
+// restartEnabled reports whether services can be restarted from the dashboard
+func (dv *DashboardView) restartEnabled() bool {
+	return dv.aetherServer != nil && len(dv.aetherServer.RestartableServices()) > 0
+}
+
+// openRestart shows the restart panel with the services that can be restarted
+func (dv *DashboardView) openRestart() {
+	p := &dv.restart
+	p.open = true
+	p.status = ""
+	p.err = ""
+	p.entries = nil
+	for _, service := range dv.aetherServer.RestartableServices() {
+		p.entries = append(p.entries, restartEntry{service: service, label: service})
+		if service == aether.ServiceEmulator {
+			p.entries = append(p.entries, restartEntry{service: service, redeploy: true, label: service + " (redeploy + init transactions)"})
+		}
+	}
+	if p.selected >= len(p.entries) {
+		p.selected = 0
+	}
+}
+
+// runRestart restarts the selected service in the background
+func (dv *DashboardView) runRestart(entry restartEntry) tea.Cmd {
+	p := &dv.restart
+	p.busy = fmt.Sprintf("Restarting %s...", entry.service)
+	p.status = ""
+	p.err = ""
+
+	// The dashboard shows what the restarted services report again
+	if entry.redeploy {
+		dv.initTransactions = []InitTransactionStatus{}
+	}
+	if entry.service == aether.ServiceFrontend {
+		dv.frontendPorts = []string{}
+		dv.frontendStatus = "Starting..."
+	}
+
+	server := dv.aetherServer
+	return func() tea.Msg {
+		return restartResultMsg{entry: entry, err: server.RestartService(entry.service, entry.redeploy)}
+	}
+}
+
+// updateRestart handles keys while the restart panel is open and the result of a restart
+func (dv *DashboardView) updateRestart(msg tea.Msg) tea.Cmd {
+	p := &dv.restart
+
+	switch msg := msg.(type) {
+	case restartResultMsg:
+		p.busy = ""
+		if msg.err != nil {
+			dv.logger.Error().Err(msg.err).Str("service", msg.entry.service).Msg("Restart failed")
+			p.err = msg.err.Error()
+			return nil
+		}
+		p.status = fmt.Sprintf("Restarted %s", msg.entry.label)
+		return nil
+
+	case tea.KeyMsg:
+		// Ignore keys while a restart is running
+		if p.busy != "" {
+			return nil
+		}
+
+		switch {
+		case key.Matches(msg, dv.keys.Close):
+			p.open = false
+		case key.Matches(msg, dv.keys.Up):
+			if p.selected > 0 {
+				p.selected--
+			}
+		case key.Matches(msg, dv.keys.Down):
+			if p.selected < len(p.entries)-1 {
+				p.selected++
+			}
+		case key.Matches(msg, dv.keys.RestartSelected):
+			if len(p.entries) == 0 {
+				return nil
+			}
+			return dv.runRestart(p.entries[p.selected])
+		}
+	}
+
+	return nil
+}
+
+// renderRestartBox renders the restart panel in place of the services box
+func (dv *DashboardView) renderRestartBox(width, height int) string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlightColor).
+		PaddingLeft(1).
+		PaddingRight(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlightColor).
+		Background(base02).
+		PaddingLeft(1).
+		PaddingRight(1).
+		MarginBottom(1)
+
+	p := dv.restart
+	var content strings.Builder
+
+	content.WriteString(headerStyle.Render("🔄 Restart Service") + "\n\n")
+	content.WriteString(dimStyle.Render("enter restart, esc close") + "\n\n")
+
+	for i, entry := range p.entries {
+		if i == p.selected {
+			line := lipgloss.NewStyle().
+				Foreground(highlightColor).
+				Bold(true).
+				Render("▶ " + entry.label)
+			content.WriteString(line + "\n")
+		} else {
+			content.WriteString(dimStyle.Render("  "+entry.label) + "\n")
+		}
+	}
+
+	if p.entries != nil && p.entries[p.selected].service == aether.ServiceEmulator {
+		content.WriteString("\n" + dimStyle.Render("The emulator starts over, the dev wallet and EVM gateway restart with it") + "\n")
+	}
+
+	if p.busy != "" {
+		content.WriteString("\n" + dimStyle.Render(p.busy))
+	} else if p.err != "" {
+		content.WriteString("\n" + lipgloss.NewStyle().Foreground(errorColor).Render("✗ "+p.err))
+	} else if p.status != "" {
+		content.WriteString("\n" + lipgloss.NewStyle().Foreground(successColor).Render("✓ "+p.status))
+	}
+
+	return boxStyle.Render(content.String())
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// snapshotPanel is the dashboard panel to create, revert to and delete emulator snapshots
+type snapshotPanel struct {
+	open     bool
+	loading  bool
+	busy     string // Action in progress, e.g. "Reverting to 'foo'..."
+	names    []string
+	selected int
+	naming   bool            // Whether the new snapshot name input is active
+	input    textinput.Model // Input for the new snapshot name
+	status   string          // Result of the last action
+	err      string          // Error from the last action
+}
+
+// snapshotsLoadedMsg is sent when the snapshot list has been read from the emulator
+type snapshotsLoadedMsg struct {
+	names []string
+	err   error
+}
+
+// snapshotResultMsg is sent when a snapshot action has finished
+type snapshotResultMsg struct {
+	action string
+	name   string
+	err    error
+}
+
+func newSnapshotPanel() snapshotPanel {
+	input := textinput.New()
+	input.Placeholder = "snapshot name"
+	input.CharLimit = 50
+	input.Width = 30
+	return snapshotPanel{input: input}
+}
+
+// snapshotsEnabled reports whether the snapshot panel can be used
+func (dv *DashboardView) snapshotsEnabled() bool {
+	return dv.aetherServer != nil && dv.aetherServer.SnapshotsEnabled()
+}
+
+// openSnapshots shows the snapshot panel and loads the list of snapshots
+func (dv *DashboardView) openSnapshots() tea.Cmd {
+	dv.snapshots.open = true
+	dv.snapshots.status = ""
+	dv.snapshots.err = ""
+	return dv.loadSnapshots()
+}
+
+func (dv *DashboardView) loadSnapshots() tea.Cmd {
+	dv.snapshots.loading = true
+	server := dv.aetherServer
+	return func() tea.Msg {
+		names, err := server.Snapshots()
+		return snapshotsLoadedMsg{names: names, err: err}
+	}
+}
+
+// runSnapshotAction runs a snapshot action in the background and reports the result
+func (dv *DashboardView) runSnapshotAction(action, name, busy string, fn func(string) error) tea.Cmd {
+	dv.snapshots.busy = busy
+	dv.snapshots.status = ""
+	dv.snapshots.err = ""
+	return func() tea.Msg {
+		return snapshotResultMsg{action: action, name: name, err: fn(name)}
+	}
+}
+
+// updateSnapshots handles keys while the snapshot panel is open and the results of snapshot actions
+func (dv *DashboardView) updateSnapshots(msg tea.Msg) tea.Cmd {
+	p := &dv.snapshots
+
+	switch msg := msg.(type) {
+	case snapshotsLoadedMsg:
+		p.loading = false
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		p.names = msg.names
+		if p.selected >= len(p.names) {
+			p.selected = max(0, len(p.names)-1)
+		}
+		return nil
+
+	case snapshotResultMsg:
+		p.busy = ""
+		if msg.err != nil {
+			dv.logger.Error().Err(msg.err).Str("action", msg.action).Str("snapshot", msg.name).Msg("Snapshot action failed")
+			p.err = msg.err.Error()
+		} else {
+			p.status = fmt.Sprintf("%s '%s'", msg.action, msg.name)
+		}
+		return dv.loadSnapshots()
+
+	case tea.KeyMsg:
+		// Ignore keys while an action is running
+		if p.busy != "" {
+			return nil
+		}
+
+		if p.naming {
+			switch msg.Type {
+			case tea.KeyEnter:
+				name := strings.TrimSpace(p.input.Value())
+				if name == "" {
+					return nil
+				}
+				p.naming = false
+				p.input.SetValue("")
+				p.input.Blur()
+				return dv.runSnapshotAction("Created", name, fmt.Sprintf("Creating '%s'...", name), dv.aetherServer.CreateSnapshot)
+			case tea.KeyEsc:
+				p.naming = false
+				p.input.SetValue("")
+				p.input.Blur()
+				return nil
+			default:
+				var cmd tea.Cmd
+				p.input, cmd = p.input.Update(msg)
+				return cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, dv.keys.Close):
+			p.open = false
+		case key.Matches(msg, dv.keys.Up):
+			if p.selected > 0 {
+				p.selected--
+			}
+		case key.Matches(msg, dv.keys.Down):
+			if p.selected < len(p.names)-1 {
+				p.selected++
+			}
+		case key.Matches(msg, dv.keys.New):
+			p.naming = true
+			p.status = ""
+			p.err = ""
+			return p.input.Focus()
+		case key.Matches(msg, dv.keys.Revert):
+			if len(p.names) == 0 {
+				return nil
+			}
+			name := p.names[p.selected]
+			return dv.runSnapshotAction("Reverted to", name, fmt.Sprintf("Reverting to '%s'...", name), dv.aetherServer.RevertToSnapshot)
+		case key.Matches(msg, dv.keys.Delete):
+			if len(p.names) == 0 {
+				return nil
+			}
+			name := p.names[p.selected]
+			return dv.runSnapshotAction("Deleted", name, fmt.Sprintf("Deleting '%s'...", name), dv.aetherServer.DeleteSnapshot)
+		}
+	}
+
+	return nil
+}
+
+// renderSnapshotsBox renders the snapshot panel in place of the init transactions box
+func (dv *DashboardView) renderSnapshotsBox(width, height int) string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlightColor).
+		PaddingLeft(1).
+		PaddingRight(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlightColor).
+		Background(base02).
+		PaddingLeft(1).
+		PaddingRight(1).
+		MarginBottom(1)
+
+	p := dv.snapshots
+	var content strings.Builder
+
+	content.WriteString(headerStyle.Render(fmt.Sprintf("📸 Snapshots (%d)", len(p.names))) + "\n\n")
+	content.WriteString(dimStyle.Render("n new, enter revert, d delete, esc close") + "\n\n")
+
+	if p.naming {
+		content.WriteString(labelStyle.Render("Name: ") + p.input.View() + "\n\n")
+	}
+
+	if p.loading && len(p.names) == 0 {
+		content.WriteString(dimStyle.Render("Loading snapshots...") + "\n")
+	} else if len(p.names) == 0 {
+		content.WriteString(dimStyle.Render("No snapshots yet") + "\n")
+	}
+
+	for i, name := range p.names {
+		if i == p.selected {
+			line := lipgloss.NewStyle().
+				Foreground(highlightColor).
+				Bold(true).
+				Render("▶ " + name)
+			content.WriteString(line + "\n")
+		} else {
+			content.WriteString(dimStyle.Render("  "+name) + "\n")
+		}
+	}
+
+	if p.busy != "" {
+		content.WriteString("\n" + dimStyle.Render(p.busy))
+	} else if p.err != "" {
+		content.WriteString("\n" + lipgloss.NewStyle().Foreground(errorColor).Render("✗ "+p.err))
+	} else if p.status != "" {
+		content.WriteString("\n" + lipgloss.NewStyle().Foreground(successColor).Render("✓ "+p.status))
+	}
+
+	return boxStyle.Render(content.String())
+}
@@ -4,32 +4,40 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/bjartek/aether/pkg/aether"
 	"github.com/bjartek/aether/pkg/config"
 	"github.com/bjartek/aether/pkg/events"
+	"github.com/bjartek/aether/pkg/health"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
 	"github.com/rs/zerolog"
 )
 
 // DashboardView displays live system status in three boxes
 type DashboardView struct {
-	// Services box
-	services      []ServiceInfo
-	servicesReady bool
+	// Services box, updated by the health monitor
+	services []ServiceInfo
 
 	// Init transactions box
 	initTransactions []InitTransactionStatus
 	initComplete     bool
+	contractReloads  []aether.ContractReloadMsg // Latest contract hot reloads, newest last
 
 	// Block height box
 	latestBlockHeight uint64
+	latestBlockTime   time.Time // Emulated time when time travelling on the emulator
+	timestampFormat   string
 	network           string
 	blockTime         string
 	indexerPolling    string
+	indexRange        *aether.IndexRangeMsg    // Set while backfilling from indexer.start_height
+	indexerDegraded   *aether.IndexerStatusMsg // Set while the indexer retries a block it could not fetch
+	indexerFilter     *aether.IndexerFilterMsg // Set when indexer.filter drops transactions
 
 	// Accounts box
 	accountRegistry *aether.AccountRegistry
@@ -42,6 +50,21 @@ type DashboardView struct {
 	interactiveMode     bool           // If true, user needs to select folder
 	aetherServer        *aether.Aether // Reference to server for running init transactions
 
+	// Snapshot panel (emulator only)
+	snapshots snapshotPanel
+	restored  bool // True if the emulator state was restored from disk
+	keys      DashboardKeyMap
+
+	// Coverage report (emulator with coverage enabled only)
+	coverageStatus string
+	coverageErr    string
+
+	// Committing blocks and time travel (emulator only)
+	blocks blockControls
+
+	// Restart panel (emulator only)
+	restart restartPanel
+
 	// Frontend box
 	frontendCommand string
 	frontendStatus  string // "Running" or "Stopped"
@@ -56,7 +79,8 @@ type DashboardView struct {
 type ServiceInfo struct {
 	Name   string
 	Port   string
-	Status string
+	Status health.Status
+	Error  string // Error of the last probe when not ready
 }
 
 type InitTransactionStatus struct {
@@ -74,13 +98,23 @@ func NewDashboardViewWithConfig(cfg *config.Config, logger zerolog.Logger, aethe
 
 	// Build services list from config ports
 	services := []ServiceInfo{
-		{Name: "Flow Emulator (gRPC)", Port: fmt.Sprintf("%d", cfg.Ports.Emulator.GRPC), Status: "Starting..."},
-		{Name: "Flow Emulator (REST)", Port: fmt.Sprintf("%d", cfg.Ports.Emulator.REST), Status: "Starting..."},
-		{Name: "Flow Emulator (Admin)", Port: fmt.Sprintf("%d", cfg.Ports.Emulator.Admin), Status: "Starting..."},
-		{Name: "Flow Emulator (Debugger)", Port: fmt.Sprintf("%d", cfg.Ports.Emulator.Debugger), Status: "Starting..."},
-		{Name: "Dev Wallet", Port: fmt.Sprintf("%d", cfg.Ports.DevWallet), Status: "Starting..."},
-		{Name: "EVM Gateway (JSON-RPC)", Port: fmt.Sprintf("%d", cfg.Ports.EVM.RPC), Status: "Starting..."},
-		{Name: "EVM Gateway (Profiler)", Port: fmt.Sprintf("%d", cfg.Ports.EVM.Profiler), Status: "Starting..."},
+		{Name: health.EmulatorGRPC, Port: fmt.Sprintf("%d", cfg.Ports.Emulator.GRPC), Status: health.StatusStarting},
+		{Name: health.EmulatorREST, Port: fmt.Sprintf("%d", cfg.Ports.Emulator.REST), Status: health.StatusStarting},
+		{Name: health.EmulatorAdmin, Port: fmt.Sprintf("%d", cfg.Ports.Emulator.Admin), Status: health.StatusStarting},
+		{Name: health.EmulatorDebugger, Port: fmt.Sprintf("%d", cfg.Ports.Emulator.Debugger), Status: health.StatusStarting},
+		{Name: health.DevWallet, Port: fmt.Sprintf("%d", cfg.Ports.DevWallet), Status: health.StatusStarting},
+	}
+	// The EVM gateway is not started without the EVM contracts
+	if cfg.Flow.Emulator.SetupEVMEnabled {
+		services = append(services,
+			ServiceInfo{Name: health.EVMGatewayRPC, Port: fmt.Sprintf("%d", cfg.Ports.EVM.RPC), Status: health.StatusStarting},
+			ServiceInfo{Name: health.EVMGatewayPprof, Port: fmt.Sprintf("%d", cfg.Ports.EVM.Profiler), Status: health.StatusStarting},
+		)
+	}
+
+	blockTime := cfg.Flow.BlockTime.String()
+	if cfg.Flow.ManualBlocks {
+		blockTime = config.ManualBlockTime
 	}
 
 	frontendStatus := "Not configured"
@@ -90,12 +124,12 @@ func NewDashboardViewWithConfig(cfg *config.Config, logger zerolog.Logger, aethe
 
 	return &DashboardView{
 		services:            services,
-		servicesReady:       false,
 		initTransactions:    []InitTransactionStatus{},
 		initComplete:        false,
 		latestBlockHeight:   0,
+		timestampFormat:     cfg.Indexer.Underflow.TimestampFormat,
 		network:             cfg.Network,
-		blockTime:           cfg.Flow.BlockTime.String(),
+		blockTime:           blockTime,
 		indexerPolling:      cfg.Indexer.PollingInterval.String(),
 		accountRegistry:     nil,
 		accounts:            []string{},
@@ -107,6 +141,9 @@ func NewDashboardViewWithConfig(cfg *config.Config, logger zerolog.Logger, aethe
 		frontendCommand:     cfg.FrontendCommand,
 		frontendStatus:      frontendStatus,
 		frontendPorts:       []string{},
+		snapshots:           newSnapshotPanel(),
+		blocks:              newBlockControls(),
+		keys:                DefaultDashboardKeyMap(),
 		logger:              logger,
 	}
 }
@@ -123,18 +160,21 @@ func (dv *DashboardView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		dv.width = msg.Width
 		dv.height = msg.Height
 
-	case aether.OverflowReadyMsg:
-		// Mark services as ready
-		dv.servicesReady = true
+	case health.StatusMsg:
 		for i := range dv.services {
-			dv.services[i].Status = "Running"
+			if dv.services[i].Name == msg.Service {
+				dv.services[i].Status = msg.Status
+				dv.services[i].Error = msg.Error
+			}
 		}
+
+	case aether.OverflowReadyMsg:
 		// Store account registry and get account names
 		dv.accountRegistry = msg.AccountRegistry
 		if dv.accountRegistry != nil {
 			dv.accounts = dv.accountRegistry.GetAllNames()
 		}
-		dv.logger.Debug().Msg("Services marked as ready")
+		dv.restored = msg.Restored
 
 	case aether.InitTransactionMsg:
 		// Add init transaction status
@@ -148,12 +188,48 @@ func (dv *DashboardView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Bool("success", msg.Success).
 			Msg("Init transaction status received")
 
+	case aether.ContractReloadMsg:
+		dv.contractReloaded(msg)
+
 	case aether.BlockHeightMsg:
 		// Update block height
 		if msg.Height > dv.latestBlockHeight {
 			dv.latestBlockHeight = msg.Height
+			dv.latestBlockTime = msg.Timestamp
+		}
+
+	case aether.IndexRangeMsg:
+		dv.indexRange = &msg
+
+	case aether.IndexerStatusMsg:
+		if msg.Degraded {
+			dv.indexerDegraded = &msg
+		} else {
+			dv.indexerDegraded = nil
 		}
 
+	case aether.IndexerFilterMsg:
+		dv.indexerFilter = &msg
+
+	case aether.IndexerResetMsg:
+		// Blocks are indexed again from the start, e.g. after reverting to a snapshot
+		dv.latestBlockHeight = 0
+		dv.latestBlockTime = time.Time{}
+		dv.indexerDegraded = nil
+		dv.indexerFilter = nil
+
+	case snapshotsLoadedMsg, snapshotResultMsg:
+		return dv, dv.updateSnapshots(msg)
+
+	case coverageWrittenMsg:
+		dv.coverageWritten(msg)
+
+	case blocksCommittedMsg:
+		dv.blocksCommitted(msg)
+
+	case restartResultMsg:
+		return dv, dv.updateRestart(msg)
+
 	case aether.InitFolderSelectionMsg:
 		// Store folder selection options
 		dv.folderSelection = &msg
@@ -205,6 +281,37 @@ func (dv *DashboardView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				return dv, nil
 			}
+			return dv, nil
+		}
+
+		// Handle snapshot panel
+		if dv.snapshots.open {
+			return dv, dv.updateSnapshots(msg)
+		}
+		if dv.restart.open {
+			return dv, dv.updateRestart(msg)
+		}
+		if dv.blocks.prompt != "" {
+			return dv, dv.updateBlockInput(msg)
+		}
+		if key.Matches(msg, dv.keys.Snapshots) && dv.snapshotsEnabled() {
+			return dv, dv.openSnapshots()
+		}
+		if key.Matches(msg, dv.keys.Restart) && dv.restartEnabled() {
+			dv.openRestart()
+			return dv, nil
+		}
+		if key.Matches(msg, dv.keys.Coverage) && dv.coverageEnabled() {
+			return dv, dv.writeCoverage()
+		}
+		if key.Matches(msg, dv.keys.CommitBlock) && dv.manualBlocksEnabled() && !dv.blocks.busy {
+			return dv, dv.commitBlocks(1)
+		}
+		if key.Matches(msg, dv.keys.CommitBlocks) && dv.timeTravelEnabled() && !dv.blocks.busy {
+			return dv, dv.promptBlockInput(promptBlocks)
+		}
+		if key.Matches(msg, dv.keys.TimeTravel) && dv.timeTravelEnabled() && !dv.blocks.busy {
+			return dv, dv.promptBlockInput(promptTime)
 		}
 	}
 
@@ -228,14 +335,20 @@ func (dv *DashboardView) View() string {
 	var boxes string
 
 	// Show different boxes based on network
-	if dv.network == "emulator" {
+	if config.IsEmulator(dv.network) {
 		// Emulator: show all four boxes
 		boxWidth := (dv.width - 8) / 4 // -8 for spacing between boxes
 		boxHeight := dv.height - 5     // -5 for title and padding
 
 		servicesBox := dv.renderServicesBox(boxWidth, boxHeight)
+		if dv.restart.open {
+			servicesBox = dv.renderRestartBox(boxWidth, boxHeight)
+		}
 		accountsBox := dv.renderAccountsBox(boxWidth, boxHeight)
 		initBox := dv.renderInitTransactionsBox(boxWidth, boxHeight)
+		if dv.snapshots.open {
+			initBox = dv.renderSnapshotsBox(boxWidth, boxHeight)
+		}
 		blockHeightBox := dv.renderBlockHeightBox(boxWidth, boxHeight)
 
 		boxes = lipgloss.JoinHorizontal(
@@ -297,11 +410,7 @@ func (dv *DashboardView) renderServicesBox(width, height int) string {
 	var content strings.Builder
 
 	// Header
-	if dv.servicesReady {
-		content.WriteString(headerStyle.Render("✓ Services Running") + "\n\n")
-	} else {
-		content.WriteString(headerStyle.Render("⏳ Starting Services...") + "\n\n")
-	}
+	content.WriteString(headerStyle.Render(dv.servicesHeader()) + "\n\n")
 
 	// Services list
 	if len(dv.services) == 0 {
@@ -320,9 +429,16 @@ func (dv *DashboardView) renderServicesBox(width, height int) string {
 			statusColor := mutedColor
 			statusSymbol := "⏳"
 
-			if svc.Status == "Running" {
+			switch svc.Status {
+			case health.StatusReady:
 				statusColor = successColor
 				statusSymbol = "✓"
+			case health.StatusDegraded:
+				statusColor = highlightColor
+				statusSymbol = "⚠"
+			case health.StatusStopped:
+				statusColor = errorColor
+				statusSymbol = "✗"
 			}
 
 			// Pad service name to align ports
@@ -334,12 +450,34 @@ func (dv *DashboardView) renderServicesBox(width, height int) string {
 				dimStyle.Render(svc.Port),
 			)
 			content.WriteString(line)
+			if (svc.Status == health.StatusDegraded || svc.Status == health.StatusStopped) && svc.Error != "" {
+				content.WriteString(dimStyle.Render("  "+svc.Error) + "\n")
+			}
 		}
 	}
 
 	return boxStyle.Render(content.String())
 }
 
+// servicesHeader summarizes the status of all services, the worst status wins
+func (dv *DashboardView) servicesHeader() string {
+	ready := 0
+	for _, svc := range dv.services {
+		switch svc.Status {
+		case health.StatusStopped:
+			return "✗ Services Stopped"
+		case health.StatusDegraded:
+			return "⚠ Services Degraded"
+		case health.StatusReady:
+			ready++
+		}
+	}
+	if ready == len(dv.services) {
+		return "✓ Services Running"
+	}
+	return "⏳ Starting Services..."
+}
+
 // renderAccountsBox renders the accounts box showing dev-wallet accounts and funding status
 func (dv *DashboardView) renderAccountsBox(width, height int) string {
 	boxStyle := lipgloss.NewStyle().
@@ -461,7 +599,10 @@ func (dv *DashboardView) renderInitTransactionsBox(width, height int) string {
 		content.WriteString(headerStyle.Render("⏳ Init Transactions") + "\n\n")
 
 		// Show appropriate message based on mode and network
-		if dv.network == "emulator" {
+		if dv.restored {
+			content.WriteString(dimStyle.Render("Restored persisted emulator state") + "\n\n")
+			content.WriteString(dimStyle.Render("Contracts and init transactions are already in place"))
+		} else if config.IsEmulator(dv.network) {
 			if dv.interactiveMode && dv.selectedInitFolder == "" {
 				// Interactive mode and no folder selected yet
 				content.WriteString(dimStyle.Render("Waiting to select folder..."))
@@ -517,6 +658,8 @@ func (dv *DashboardView) renderInitTransactionsBox(width, height int) string {
 		}
 	}
 
+	content.WriteString(dv.renderContractReloads())
+
 	return boxStyle.Render(content.String())
 }
 
@@ -545,12 +688,19 @@ func (dv *DashboardView) renderBlockHeightBox(width, height int) string {
 	content.WriteString(labelStyle.Render("Network: ") + valueStyle.Render(dv.network) + "\n")
 
 	// Only show block time for emulator (it's configurable there)
-	if dv.network == "emulator" {
+	if config.IsEmulator(dv.network) {
 		content.WriteString(dimStyle.Render(fmt.Sprintf("Block time: %s", dv.blockTime)) + "\n")
 	}
 
 	content.WriteString(dimStyle.Render(fmt.Sprintf("Polling: %s", dv.indexerPolling)) + "\n\n")
 
+	if d := dv.indexerDegraded; d != nil {
+		degradedStyle := lipgloss.NewStyle().Bold(true).Foreground(errorColor)
+		content.WriteString(degradedStyle.Render("⚠ Indexer degraded") + "\n")
+		content.WriteString(dimStyle.Render(fmt.Sprintf("Block %d failed %d times, retry in %s", d.Height, d.Failures, d.RetryIn)) + "\n")
+		content.WriteString(dimStyle.Render(truncate.StringWithTail(d.Error, uint(max(width-4, 10)), "…")) + "\n\n")
+	}
+
 	// Block height
 	if dv.latestBlockHeight == 0 {
 		content.WriteString(dimStyle.Render("Waiting for first block..."))
@@ -560,13 +710,49 @@ func (dv *DashboardView) renderBlockHeightBox(width, height int) string {
 			Foreground(successColor)
 
 		content.WriteString(blockStyle.Render(fmt.Sprintf("Height: %d", dv.latestBlockHeight)))
+		if t := dv.renderEmulatedTime(); t != "" {
+			content.WriteString("\n" + t)
+		}
 		content.WriteString("\n\n")
-		content.WriteString(dimStyle.Render("Blockchain is live"))
+		if dv.indexRange != nil {
+			content.WriteString(dimStyle.Render(dv.renderIndexRange()))
+		} else if dv.manualBlocksEnabled() {
+			content.WriteString(dimStyle.Render("Blocks are committed on demand, press b"))
+		} else {
+			content.WriteString(dimStyle.Render("Blockchain is live"))
+		}
+		if f := dv.indexerFilter; f != nil {
+			content.WriteString("\n" + dimStyle.Render(fmt.Sprintf("Filter: kept %d of %d transactions", f.Kept, f.Seen)))
+		}
+	}
+
+	content.WriteString(dv.renderBlockControls())
+
+	if status := dv.renderCoverageStatus(); status != "" {
+		content.WriteString("\n\n" + status)
 	}
 
 	return boxStyle.Render(content.String())
 }
 
+// renderIndexRange shows how far the backfill from indexer.start_height is
+func (dv *DashboardView) renderIndexRange() string {
+	r := dv.indexRange
+	if dv.latestBlockHeight >= r.To {
+		if r.Follow {
+			return fmt.Sprintf("Backfilled %d blocks from %d, following the network", r.To-r.From+1, r.From)
+		}
+		return fmt.Sprintf("Indexed blocks %d-%d, stopped at indexer.end_height", r.From, r.To)
+	}
+
+	total := r.To - r.From + 1
+	done := uint64(0)
+	if dv.latestBlockHeight >= r.From {
+		done = dv.latestBlockHeight - r.From + 1
+	}
+	return fmt.Sprintf("Backfill: %d/%d blocks (%d%%)", done, total, done*100/total)
+}
+
 // renderFrontendBox renders the frontend status box
 func (dv *DashboardView) renderFrontendBox(width, height int) string {
 	boxStyle := lipgloss.NewStyle().
@@ -615,18 +801,115 @@ func (dv *DashboardView) Name() string {
 
 // KeyMap implements TabbedModel interface
 func (dv *DashboardView) KeyMap() help.KeyMap {
-	return dashboardKeyMapAdapter{}
+	// Only show the actions that are available
+	keys := dv.keys
+	snapshots := dv.snapshotsEnabled()
+	restart := dv.restartEnabled()
+	for _, b := range []*key.Binding{&keys.Snapshots, &keys.New, &keys.Revert, &keys.Delete} {
+		b.SetEnabled(snapshots)
+	}
+	for _, b := range []*key.Binding{&keys.Up, &keys.Down, &keys.Close} {
+		b.SetEnabled(snapshots || restart)
+	}
+	keys.Restart.SetEnabled(restart)
+	keys.RestartSelected.SetEnabled(restart)
+	keys.Coverage.SetEnabled(dv.coverageEnabled())
+	keys.CommitBlock.SetEnabled(dv.manualBlocksEnabled())
+	keys.CommitBlocks.SetEnabled(dv.timeTravelEnabled())
+	keys.TimeTravel.SetEnabled(dv.timeTravelEnabled())
+	return keys
+}
+
+// DashboardKeyMap defines keybindings for the dashboard view
+type DashboardKeyMap struct {
+	Snapshots key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	New       key.Binding
+	Revert    key.Binding
+	Delete    key.Binding
+	Close     key.Binding
+	Coverage  key.Binding
+
+	CommitBlock  key.Binding
+	CommitBlocks key.Binding
+	TimeTravel   key.Binding
+
+	Restart         key.Binding
+	RestartSelected key.Binding
 }
 
-// dashboardKeyMapAdapter provides empty key bindings for dashboard
-type dashboardKeyMapAdapter struct{}
+// DefaultDashboardKeyMap returns the default keybindings for dashboard view
+func DefaultDashboardKeyMap() DashboardKeyMap {
+	return DashboardKeyMap{
+		Snapshots: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "snapshots"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/↑", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/↓", "down"),
+		),
+		New: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new snapshot"),
+		),
+		Revert: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "revert to snapshot"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete snapshot"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+		Coverage: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "write coverage"),
+		),
+		CommitBlock: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "commit block"),
+		),
+		CommitBlocks: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "commit n blocks"),
+		),
+		TimeTravel: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "advance time"),
+		),
+		Restart: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "restart service"),
+		),
+		RestartSelected: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "restart selected"),
+		),
+	}
+}
 
-func (k dashboardKeyMapAdapter) ShortHelp() []key.Binding {
-	return []key.Binding{}
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k DashboardKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Snapshots, k.Coverage, k.CommitBlock, k.TimeTravel, k.Restart}
 }
 
-func (k dashboardKeyMapAdapter) FullHelp() [][]key.Binding {
-	return [][]key.Binding{}
+// FullHelp returns keybindings for the expanded help view
+func (k DashboardKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Snapshots, k.Coverage, k.CommitBlock, k.CommitBlocks, k.TimeTravel, k.Restart},
+		{k.Up, k.Down},
+		{k.New, k.Revert, k.Delete, k.Close},
+		{k.RestartSelected},
+	}
 }
 
 // FooterView implements TabbedModel interface
@@ -636,6 +919,6 @@ func (dv *DashboardView) FooterView() string {
 
 // IsCapturingInput implements TabbedModel interface
 func (dv *DashboardView) IsCapturingInput() bool {
-	// Capture input when folder selection is active
-	return dv.folderSelection != nil
+	// Capture input when folder selection, the snapshot or restart panel or a block input is active
+	return dv.folderSelection != nil || dv.snapshots.open || dv.restart.open || dv.blocks.prompt != ""
 }
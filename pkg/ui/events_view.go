@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/bjartek/aether/pkg/aether"
 	"github.com/bjartek/aether/pkg/config"
@@ -70,6 +71,10 @@ type EventsView struct {
 	showRawAddresses bool
 	timeFormat       string             // Time format from config
 	events           []aether.EventData // Store original data for rebuilding
+	sortOrder        string             // "asc" or "desc", how rows are ordered relative to events
+	maxEvents        int                // ui.history.max_events, older ones are paged in from the history
+	history          historyPager       // Place in the history of every event
+	seen             map[string]bool    // Keys of the events shown
 	logger           zerolog.Logger     // Debug logger
 }
 
@@ -113,6 +118,9 @@ func NewEventsViewWithConfig(cfg *config.Config, logger zerolog.Logger) *EventsV
 		keys:             DefaultEventsKeyMap(),
 		showRawAddresses: cfg.UI.Defaults.ShowRawAddresses,
 		timeFormat:       cfg.UI.Defaults.TimeFormat,
+		sortOrder:        cfg.UI.Defaults.Sort,
+		maxEvents:        cfg.UI.History.MaxEvents,
+		seen:             make(map[string]bool),
 		logger:           logger,
 	}
 }
@@ -131,12 +139,37 @@ func (ev *EventsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case aether.BlockEventMsg:
 		// Handle incoming event data
-		ev.AddEvent(msg.EventData)
+		ev.AddEvent(msg.EventData, msg.HistorySeq)
+		return ev, nil
+
+	case aether.HistoryLoadedMsg:
+		// Events of earlier runs, shown before the ones indexed since
+		ev.history.history = msg.History
+		ev.prependEvents(msg.Events)
+		return ev, nil
+
+	case eventsPageMsg:
+		if msg.err != nil {
+			ev.logger.Debug().Err(msg.err).Msg("Failed to read events from the history")
+		}
+		if ev.history.loaded(msg.before, len(msg.events), msg.err) {
+			ev.prependEvents(msg.events)
+		}
+		return ev, nil
+
+	case aether.IndexerResetMsg:
+		// Everything is indexed again
+		ev.events = nil
+		ev.seen = make(map[string]bool)
+		ev.history.reset()
+		ev.sv.SetRows([]splitview.RowData{})
 		return ev, nil
 
 	case aether.OverflowReadyMsg:
 		// Set account registry when ready
 		ev.SetAccountRegistry(msg.AccountRegistry)
+		// Events loaded from the history before were shown without names
+		ev.refreshAllRows()
 		return ev, nil
 
 	case tea.KeyMsg:
@@ -151,9 +184,33 @@ func (ev *EventsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	_, cmd := ev.sv.Update(msg)
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && ev.scrolledToOldest(keyMsg) {
+		cmd = tea.Batch(cmd, ev.loadOlderEvents())
+	}
 	return ev, cmd
 }
 
+// scrolledToOldest reports whether a key moved the cursor to the oldest event, or tried to move past it
+func (ev *EventsView) scrolledToOldest(msg tea.KeyMsg) bool {
+	if ev.sv.IsFullscreen() || len(ev.events) == 0 {
+		return false
+	}
+	if ev.sortOrder == "desc" {
+		return ev.sv.GetCursor() == len(ev.events)-1 &&
+			(key.Matches(msg, ev.keys.LineDown) || key.Matches(msg, ev.keys.GotoEnd))
+	}
+	return ev.sv.GetCursor() == 0 && (key.Matches(msg, ev.keys.LineUp) || key.Matches(msg, ev.keys.GotoTop))
+}
+
+// loadOlderEvents reads the events before the oldest one shown from the history
+func (ev *EventsView) loadOlderEvents() tea.Cmd {
+	h := ev.history.history
+	return ev.history.load(func(before uint64) tea.Msg {
+		events, err := h.Events(before, historyPageSize)
+		return eventsPageMsg{before: before, events: events, err: err}
+	})
+}
+
 // View delegates to splitview
 func (ev *EventsView) View() string {
 	ev.logger.Debug().Str("method", "View").Msg("EventsView.View called")
@@ -214,17 +271,113 @@ func (ev *EventsView) SetAccountRegistry(registry *aether.AccountRegistry) {
 	ev.accountRegistry = registry
 }
 
-// AddEvent accepts EventData and converts it to a splitview row
-func (ev *EventsView) AddEvent(eventData aether.EventData) {
+// eventKey identifies an event, the same event is indexed again when a persisted emulator is restarted
+func eventKey(eventData aether.EventData) string {
+	return fmt.Sprintf("%s-%d", eventData.TransactionID, eventData.EventIndex)
+}
+
+// AddEvent accepts EventData and converts it to a splitview row.
+// historySeq is its place in the history, 0 if it is not stored.
+func (ev *EventsView) AddEvent(eventData aether.EventData, historySeq uint64) {
+	// Indexed again while it is shown from the history
+	id := eventKey(eventData)
+	if ev.seen[id] {
+		ev.replaceEvent(eventData, historySeq)
+		return
+	}
+	ev.seen[id] = true
+
 	// Store event data for rebuilding (always append to internal array)
 	ev.events = append(ev.events, eventData)
+	ev.history.add(historySeq)
 
 	// Add row to splitview (it handles sort order internally)
 	ev.addEventRow(eventData)
+
+	// Only ui.history.max_events are kept, older ones can be paged in again from the history
+	if len(ev.events) > ev.maxEvents {
+		ev.dropOldestEvent()
+	}
+}
+
+// replaceEvent updates an event that is shown already
+func (ev *EventsView) replaceEvent(eventData aether.EventData, historySeq uint64) {
+	id := eventKey(eventData)
+	for i, e := range ev.events {
+		if eventKey(e) != id {
+			continue
+		}
+		ev.events[i] = eventData
+		ev.history.seqs[i] = historySeq
+		row := i
+		if ev.sortOrder == "desc" {
+			// Newest first, so rows are in reverse order of the stored events
+			row = len(ev.events) - 1 - i
+		}
+		ev.sv.UpdateRow(row, ev.eventRow(eventData))
+		return
+	}
+}
+
+// dropOldestEvent removes the oldest event from the view
+func (ev *EventsView) dropOldestEvent() {
+	delete(ev.seen, eventKey(ev.events[0]))
+	ev.events = ev.events[1:]
+	ev.history.dropOldest(1)
+
+	rows := ev.sv.GetRows()
+	cursor := ev.sv.GetCursor()
+	if ev.sortOrder == "desc" {
+		ev.sv.SetRows(rows[:len(rows)-1])
+	} else {
+		ev.sv.SetRows(rows[1:])
+		cursor-- // Keep the same event selected
+	}
+	ev.sv.SetCursor(max(cursor, 0))
+}
+
+// prependEvents adds events from the history before the oldest one shown, skipping the ones shown already
+func (ev *EventsView) prependEvents(msgs []aether.BlockEventMsg) {
+	older := make([]aether.EventData, 0, len(msgs))
+	seqs := make([]uint64, 0, len(msgs))
+	rows := make([]splitview.RowData, 0, len(msgs))
+	for _, msg := range msgs {
+		id := eventKey(msg.EventData)
+		if ev.seen[id] {
+			continue
+		}
+		ev.seen[id] = true
+		older = append(older, msg.EventData)
+		seqs = append(seqs, msg.HistorySeq)
+		rows = append(rows, ev.eventRow(msg.EventData))
+	}
+	if len(older) == 0 {
+		return
+	}
+	ev.events = append(older, ev.events...)
+	ev.history.prepend(seqs)
+
+	cursor := ev.sv.GetCursor()
+	current := ev.sv.GetRows()
+	if ev.sortOrder == "desc" {
+		// Newest first, so the older events go below the others
+		slices.Reverse(rows)
+		ev.sv.SetRows(append(current, rows...))
+	} else {
+		ev.sv.SetRows(append(rows, current...))
+		cursor += len(rows) // Keep the same event selected
+	}
+	ev.sv.SetCursor(cursor)
 }
 
 // addEventRow builds a splitview row from event data
 func (ev *EventsView) addEventRow(eventData aether.EventData) {
+	// Add to splitview (events don't have code)
+	ev.sv.AddRow(ev.eventRow(eventData))
+}
+
+// eventRow builds the splitview row of an event
+func (ev *EventsView) eventRow(eventData aether.EventData) splitview.RowData {
 	row := table.Row{
 		eventData.Timestamp.Format(ev.timeFormat),
 		fmt.Sprintf("%d", eventData.BlockHeight),
@@ -236,8 +389,7 @@ func (ev *EventsView) addEventRow(eventData aether.EventData) {
 	// Build detail content
 	content := buildEventDetailContent(eventData, ev.accountRegistry, ev.showRawAddresses)
 
-	// Add to splitview (events don't have code)
-	ev.sv.AddRow(splitview.NewRowData(row).WithContent(content))
+	return splitview.NewRowData(row).WithContent(content)
 }
 
 // refreshAllRows rebuilds all rows to reflect toggle changes
@@ -67,6 +67,12 @@ func formatSimpleValue(val interface{}, registry *aether.AccountRegistry, showRa
 				return name
 			}
 		}
+		// EVM events have addresses as hex strings without 0x, e.g. the address of a new COA
+		if !showRawAddresses && registry != nil && isUnprefixedEVMAddress(v) {
+			if name := registry.GetName("0x" + v); name != "0x"+v {
+				return name
+			}
+		}
 		// Wrap text if maxWidth is specified and string is long
 		if maxWidth > 0 && len(v) > maxWidth-len(indent) {
 			// Calculate available width accounting for indent
@@ -130,6 +136,11 @@ func isFlowAddress(s string) bool {
 	return len(hexPart) > 0
 }
 
+// isUnprefixedEVMAddress checks if a string is a 20 byte hex address without 0x
+func isUnprefixedEVMAddress(s string) bool {
+	return len(s) == 40 && isFlowAddress("0x"+s)
+}
+
 // formatValue recursively formats a value with the given indentation
 func formatValue(val interface{}, indent string, registry *aether.AccountRegistry, showRawAddresses bool, maxWidth int) string {
 	switch v := val.(type) {
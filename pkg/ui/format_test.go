@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bjartek/aether/pkg/aether"
 	"github.com/hexops/autogold"
 )
 
@@ -371,3 +372,28 @@ func TestFormatFieldValueWithRegistry_LongHexString(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatFieldValueWithRegistry_EVMAddresses(t *testing.T) {
+	registry := aether.NewAccountRegistry(nil)
+	registry.AddCOA("alice", "0x000000000000000000000002a5b1f8d0c1e8c1a4")
+	registry.AddEVMContract("token", "0x5FbDB2315678afecb367f032d93F642f64180aa3")
+
+	tests := []struct {
+		name string
+		val  string
+		want string
+	}{
+		{"coa with prefix", "0x000000000000000000000002A5B1F8D0C1E8C1A4", "alice-coa"},
+		{"coa in an EVM event", "000000000000000000000002a5b1f8d0c1e8c1a4", "alice-coa"},
+		{"contract", "0x5fbdb2315678afecb367f032d93f642f64180aa3", "token"},
+		{"unknown", "0x70997970c51812dc3a010c7d01b50e0d17dc79c8", "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatFieldValueWithRegistry(tt.val, "", registry, false, 0); got != tt.want {
+				t.Errorf("FormatFieldValueWithRegistry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/logs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyPageSize is how many older entries a view reads from the history at a time
+const historyPageSize = 200
+
+// historyPager pages older entries in from the history when a view is scrolled to the oldest entry it has.
+// It keeps the place in the history of every entry of the view, pages are read from before the oldest one.
+type historyPager struct {
+	history   *aether.History // Nil when the history is not persisted
+	seqs      []uint64        // Place in the history of every entry of the view, oldest first, 0 if not stored
+	loading   bool
+	exhausted bool // Nothing older is stored
+}
+
+// Pages read from the history, before is where the page was read from
+type transactionsPageMsg struct {
+	before       uint64
+	transactions []aether.BlockTransactionMsg
+	err          error
+}
+
+type eventsPageMsg struct {
+	before uint64
+	events []aether.BlockEventMsg
+	err    error
+}
+
+type logsPageMsg struct {
+	before uint64
+	lines  []logs.LogLineMsg
+	err    error
+}
+
+// add records the place of an entry added as the newest one
+func (p *historyPager) add(seq uint64) {
+	p.seqs = append(p.seqs, seq)
+}
+
+// prepend records the places of entries added before the oldest one
+func (p *historyPager) prepend(seqs []uint64) {
+	p.seqs = append(seqs, p.seqs...)
+}
+
+// dropOldest records that the view no longer shows its n oldest entries, they can be paged in again
+func (p *historyPager) dropOldest(n int) {
+	p.seqs = p.seqs[n:]
+	p.exhausted = false
+}
+
+// reset is called when the view drops all its entries
+func (p *historyPager) reset() {
+	p.seqs = nil
+	p.exhausted = false
+}
+
+// oldest returns the oldest place in the history the view has
+func (p *historyPager) oldest() (uint64, bool) {
+	var oldest uint64
+	for _, seq := range p.seqs {
+		if seq != 0 && (oldest == 0 || seq < oldest) {
+			oldest = seq
+		}
+	}
+	return oldest, oldest != 0
+}
+
+// load returns a command that runs read with the place to read the older page from, or nil if there is
+// no history, a page is being read already or there is nothing older
+func (p *historyPager) load(read func(before uint64) tea.Msg) tea.Cmd {
+	if p.history == nil || p.loading || p.exhausted {
+		return nil
+	}
+	before, ok := p.oldest()
+	if !ok {
+		return nil
+	}
+	p.loading = true
+	return func() tea.Msg { return read(before) }
+}
+
+// loaded is called with a page read by load, it reports whether the page should be added to the view.
+// A page is dropped if the oldest entry of the view changed while it was read.
+func (p *historyPager) loaded(before uint64, n int, err error) bool {
+	p.loading = false
+	if err != nil {
+		return false
+	}
+	if oldest, ok := p.oldest(); !ok || oldest != before {
+		return false
+	}
+	if n < historyPageSize {
+		p.exhausted = true
+	}
+	return n > 0
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/bjartek/aether/pkg/aether"
 	"github.com/bjartek/aether/pkg/config"
 	"github.com/bjartek/aether/pkg/logs"
 	"github.com/bjartek/aether/pkg/tabbedtui"
@@ -78,6 +79,7 @@ type LogsView struct {
 	lines         []string
 	filteredLines []string
 	maxLines      int
+	history       historyPager // Place in the history of every line
 	keys          LogsKeyMapV2
 	ready         bool
 	autoScroll    bool
@@ -139,8 +141,11 @@ func (lv *LogsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case logs.LogLineMsg:
 		wasAtBottom := lv.viewport.AtBottom()
 		lv.lines = append(lv.lines, msg.Line)
+		lv.history.add(msg.HistorySeq)
+		// Only ui.history.max_log_lines are kept, older ones can be paged in again from the history
 		if len(lv.lines) > lv.maxLines {
-			lv.lines = lv.lines[len(lv.lines)-lv.maxLines:]
+			lv.lines = lv.lines[1:]
+			lv.history.dropOldest(1)
 		}
 		lv.applyFilter()
 		lv.updateViewport()
@@ -150,6 +155,21 @@ func (lv *LogsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return lv, nil
 
+	case aether.HistoryLoadedMsg:
+		// Lines logged by earlier runs, shown before the ones logged since
+		lv.history.history = msg.History
+		lv.prependLines(msg.Logs)
+		return lv, nil
+
+	case logsPageMsg:
+		if msg.err != nil {
+			lv.logger.Debug().Err(msg.err).Msg("Failed to read log lines from the history")
+		}
+		if lv.history.loaded(msg.before, len(msg.lines), msg.err) {
+			lv.prependLines(msg.lines)
+		}
+		return lv, nil
+
 	case tea.KeyMsg:
 		if lv.filterMode {
 			switch {
@@ -193,7 +213,7 @@ func (lv *LogsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, lv.keys.GotoTop):
 				lv.autoScroll = false
 				lv.viewport.GotoTop()
-				return lv, tabbedtui.InputHandled()
+				return lv, tea.Batch(tabbedtui.InputHandled(), lv.loadOlderLines())
 			case key.Matches(msg, lv.keys.LineUp),
 				key.Matches(msg, lv.keys.LineDown),
 				key.Matches(msg, lv.keys.PageUp),
@@ -205,6 +225,9 @@ func (lv *LogsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if lv.viewport.AtBottom() {
 				lv.autoScroll = true
 			}
+			if lv.viewport.AtTop() && (key.Matches(msg, lv.keys.LineUp) || key.Matches(msg, lv.keys.PageUp)) {
+				cmd = tea.Batch(cmd, lv.loadOlderLines())
+			}
 			return lv, cmd
 		}
 
@@ -223,6 +246,9 @@ func (lv *LogsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if lv.viewport.AtBottom() {
 			lv.autoScroll = true
 		}
+		if lv.viewport.AtTop() && msg.Button == tea.MouseButtonWheelUp {
+			cmd = tea.Batch(cmd, lv.loadOlderLines())
+		}
 
 		return lv, cmd
 
@@ -231,6 +257,39 @@ func (lv *LogsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return lv, nil
 }
 
+// loadOlderLines reads the lines before the oldest one shown from the history
+func (lv *LogsView) loadOlderLines() tea.Cmd {
+	h := lv.history.history
+	return lv.history.load(func(before uint64) tea.Msg {
+		lines, err := h.Logs(before, historyPageSize)
+		return logsPageMsg{before: before, lines: lines, err: err}
+	})
+}
+
+// prependLines adds lines from the history before the oldest one shown, keeping the lines in view where they are
+func (lv *LogsView) prependLines(msgs []logs.LogLineMsg) {
+	if len(msgs) == 0 {
+		return
+	}
+	older := make([]string, len(msgs))
+	seqs := make([]uint64, len(msgs))
+	for i, msg := range msgs {
+		older[i] = msg.Line
+		seqs[i] = msg.HistorySeq
+	}
+	lv.lines = append(older, lv.lines...)
+	lv.history.prepend(seqs)
+
+	before := lv.viewport.TotalLineCount()
+	lv.applyFilter()
+	lv.updateViewport()
+	if lv.autoScroll {
+		lv.viewport.GotoBottom()
+		return
+	}
+	lv.viewport.SetYOffset(lv.viewport.YOffset + lv.viewport.TotalLineCount() - before)
+}
+
 // View implements tea.Model
 func (lv *LogsView) View() string {
 	lv.logger.Debug().Str("method", "View").Msg("LogsView.View called")
@@ -0,0 +1,325 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/chroma"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/splitview"
+	"github.com/bjartek/aether/pkg/tabbedtui"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog"
+)
+
+// profileRefreshInterval is how often the computation report is read from the emulator
+const profileRefreshInterval = 2 * time.Second
+
+// profileTickMsg triggers reading the computation report
+type profileTickMsg struct{}
+
+// profilesLoadedMsg carries the computation report, most expensive first
+type profilesLoadedMsg struct {
+	profiles []aether.ProcedureProfile
+	refresh  bool // Read because the refresh key was pressed, not by the periodic refresh
+	err      error
+}
+
+// ProfileKeyMap defines keybindings for the profile view
+type ProfileKeyMap struct {
+	GotoTransaction key.Binding
+	Refresh         key.Binding
+}
+
+// DefaultProfileKeyMap returns the default keybindings for profile view
+func DefaultProfileKeyMap() ProfileKeyMap {
+	return ProfileKeyMap{
+		GotoTransaction: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "go to transaction"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+	}
+}
+
+// ProfileView lists the computation used per transaction and script, most expensive first
+type ProfileView struct {
+	sv           *splitview.SplitViewModel
+	keys         ProfileKeyMap
+	aetherServer *aether.Aether
+	network      string
+	gasLimit     uint64                    // Transaction gas limit, to show how close a transaction came to it
+	profiles     []aether.ProcedureProfile // Same order as the rows
+	sourceFiles  map[string]string         // Maps transaction ID to the file that sent it
+	logger       zerolog.Logger
+}
+
+// NewProfileViewWithConfig creates a new profile view based on splitview
+func NewProfileViewWithConfig(cfg *config.Config, logger zerolog.Logger, aetherServer *aether.Aether) *ProfileView {
+	// Fallback to defaults when cfg is nil
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	columns := []splitview.ColumnConfig{
+		{Name: "Comp", Width: 7},    // Computation used
+		{Name: "Kind", Width: 6},    // tx or script
+		{Name: "ID", Width: 9},      // Truncated transaction or script ID
+		{Name: "Source", Width: 25}, // Source file
+	}
+
+	// Table styles (reuse v1 styles)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(borderColor).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(base03).
+		Background(solarYellow).
+		Bold(false)
+
+	sv := splitview.NewSplitView(
+		columns,
+		splitview.WithTableStyles(s),
+		splitview.WithTableSplitPercent(float64(cfg.UI.Layout.TransactionsSplitPercent)/100.0),
+	)
+
+	return &ProfileView{
+		sv:           sv,
+		keys:         DefaultProfileKeyMap(),
+		aetherServer: aetherServer,
+		network:      cfg.Network,
+		gasLimit:     cfg.Flow.Emulator.TransactionMaxGasLimit,
+		sourceFiles:  make(map[string]string),
+		logger:       logger,
+	}
+}
+
+// Init starts the splitview and the periodic refresh of the computation report
+func (pv *ProfileView) Init() tea.Cmd {
+	if !pv.enabled() {
+		return pv.sv.Init()
+	}
+	return tea.Batch(pv.sv.Init(), pv.loadProfiles(false))
+}
+
+// Update implements tea.Model interface
+func (pv *ProfileView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case profileTickMsg:
+		return pv, pv.loadProfiles(false)
+
+	case profilesLoadedMsg:
+		if msg.err != nil {
+			// Not read again until the refresh key is pressed, it would fail the same way every time
+			pv.logger.Warn().Err(msg.err).Msg("Failed to read profiles")
+			return pv, nil
+		}
+		if msg.refresh {
+			// The periodic refresh keeps its own tick going, only one of them runs
+			pv.setProfiles(msg.profiles)
+			return pv, nil
+		}
+		// Rebuild when entries were added, a script that runs again replaces its entry and needs a manual refresh
+		if len(msg.profiles) != len(pv.profiles) {
+			pv.setProfiles(msg.profiles)
+		}
+		return pv, tea.Tick(profileRefreshInterval, func(time.Time) tea.Msg { return profileTickMsg{} })
+
+	case aether.TransactionSourceMsg:
+		pv.sourceFiles[msg.TransactionID] = msg.SourceFile
+		return pv, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, pv.keys.GotoTransaction):
+			idx := pv.sv.GetCursor()
+			if idx < 0 || idx >= len(pv.profiles) || pv.profiles[idx].Kind != aether.ProcedureTransaction {
+				return pv, tabbedtui.InputHandled()
+			}
+			id := pv.profiles[idx].ID
+			return pv, tea.Batch(
+				tabbedtui.SwitchTab("Transactions"),
+				func() tea.Msg { return SelectTransactionMsg{TransactionID: id} },
+			)
+		case key.Matches(msg, pv.keys.Refresh) && pv.enabled():
+			return pv, tea.Batch(tabbedtui.InputHandled(), pv.loadProfiles(true))
+		}
+	}
+
+	_, cmd := pv.sv.Update(msg)
+	return pv, cmd
+}
+
+// View delegates to splitview
+func (pv *ProfileView) View() string {
+	if !pv.enabled() {
+		return dimStyle.Render("Profiling is only available with the local emulator, not on " + pv.network)
+	}
+	return pv.sv.View()
+}
+
+// Name implements TabbedModel interface
+func (pv *ProfileView) Name() string {
+	return "Profile"
+}
+
+// KeyMap implements TabbedModel interface
+func (pv *ProfileView) KeyMap() help.KeyMap {
+	return profileKeyMapAdapter{
+		splitviewKeys: pv.sv.KeyMap(),
+		profileKeys:   pv.keys,
+	}
+}
+
+// profileKeyMapAdapter combines splitview and profile keys
+type profileKeyMapAdapter struct {
+	splitviewKeys help.KeyMap
+	profileKeys   ProfileKeyMap
+}
+
+func (k profileKeyMapAdapter) ShortHelp() []key.Binding {
+	svHelp := k.splitviewKeys.ShortHelp()
+	return append(svHelp, k.profileKeys.GotoTransaction, k.profileKeys.Refresh)
+}
+
+func (k profileKeyMapAdapter) FullHelp() [][]key.Binding {
+	svHelp := k.splitviewKeys.FullHelp()
+	return append(svHelp, []key.Binding{k.profileKeys.GotoTransaction, k.profileKeys.Refresh})
+}
+
+// FooterView implements TabbedModel interface
+func (pv *ProfileView) FooterView() string {
+	return ""
+}
+
+// IsCapturingInput implements TabbedModel interface
+func (pv *ProfileView) IsCapturingInput() bool {
+	return false
+}
+
+func (pv *ProfileView) enabled() bool {
+	return pv.aetherServer != nil && pv.aetherServer.ProfilingEnabled()
+}
+
+// loadProfiles reads the computation report in the background, refresh is set when the refresh key asked for it
+func (pv *ProfileView) loadProfiles(refresh bool) tea.Cmd {
+	server := pv.aetherServer
+	return func() tea.Msg {
+		profiles, err := server.Profiles()
+		return profilesLoadedMsg{profiles: profiles, refresh: refresh, err: err}
+	}
+}
+
+// setProfiles replaces all rows, keeping the selected entry selected
+func (pv *ProfileView) setProfiles(profiles []aether.ProcedureProfile) {
+	selectedID := ""
+	if idx := pv.sv.GetCursor(); idx >= 0 && idx < len(pv.profiles) {
+		selectedID = pv.profiles[idx].ID
+	}
+
+	pv.profiles = profiles
+	rows := make([]splitview.RowData, 0, len(profiles))
+	selected := 0
+	for i, p := range profiles {
+		if p.ID == selectedID {
+			selected = i
+		}
+		rows = append(rows, pv.profileRow(p))
+	}
+	pv.sv.SetRows(rows)
+	pv.sv.SetCursor(selected)
+}
+
+// profileRow builds a splitview row from a profile
+func (pv *ProfileView) profileRow(p aether.ProcedureProfile) splitview.RowData {
+	kind := "tx"
+	if p.Kind == aether.ProcedureScript {
+		kind = "script"
+	}
+
+	source := pv.source(p)
+	if len(source) > 25 {
+		source = source[:22] + "..."
+	}
+
+	row := table.Row{
+		fmt.Sprintf("%d", p.Computation),
+		kind,
+		truncateHex(p.ID, 3, 3),
+		source,
+	}
+
+	code := ""
+	if p.Code != "" {
+		code = chroma.HighlightCadence(p.Code)
+	}
+	return splitview.NewRowData(row).WithContent(pv.profileDetail(p)).WithCode(code)
+}
+
+// source returns the file that sent a transaction or script, if known
+func (pv *ProfileView) source(p aether.ProcedureProfile) string {
+	if file, ok := pv.sourceFiles[p.ID]; ok {
+		return file
+	}
+	return p.Path
+}
+
+// profileDetail renders the detail of a profile with its intensity breakdown
+func (pv *ProfileView) profileDetail(p aether.ProcedureProfile) string {
+	fieldStyle := lipgloss.NewStyle().Bold(true).Foreground(secondaryColor)
+	valueStyleDetail := lipgloss.NewStyle().Foreground(accentColor)
+
+	renderField := func(label, value string) string {
+		return fieldStyle.Render(fmt.Sprintf("%-12s", label+":")) + " " + valueStyleDetail.Render(value) + "\n"
+	}
+
+	var details strings.Builder
+	if p.Kind == aether.ProcedureTransaction {
+		details.WriteString(fieldStyle.Render("Transaction Profile") + "\n\n")
+	} else {
+		details.WriteString(fieldStyle.Render("Script Profile") + "\n\n")
+	}
+
+	details.WriteString(renderField("ID", p.ID))
+	if source := pv.source(p); source != "" {
+		details.WriteString(renderField("Source", source))
+	}
+
+	computation := fmt.Sprintf("%d", p.Computation)
+	if p.Kind == aether.ProcedureTransaction && pv.gasLimit > 0 {
+		computation += fmt.Sprintf(" (%.1f%% of max gas limit %d)", 100*float64(p.Computation)/float64(pv.gasLimit), pv.gasLimit)
+	}
+	details.WriteString(renderField("Computation", computation))
+	details.WriteString(renderField("Memory", fmt.Sprintf("%d bytes (estimate)", p.Memory)))
+
+	if len(p.Arguments) > 0 {
+		details.WriteString("\n" + fieldStyle.Render("Arguments:") + "\n")
+		for _, arg := range p.Arguments {
+			details.WriteString("  " + valueStyleDetail.Render(strings.TrimSpace(arg)) + "\n")
+		}
+	}
+
+	if len(p.Intensities) > 0 {
+		details.WriteString("\n" + fieldStyle.Render("Intensities:") + "\n")
+		for _, intensity := range p.Intensities {
+			details.WriteString("  " + fieldStyle.Render(fmt.Sprintf("%-28s", intensity.Kind)) + " " + valueStyleDetail.Render(fmt.Sprintf("%d", intensity.Count)) + "\n")
+		}
+	}
+
+	if p.Code != "" {
+		details.WriteString("\n" + fieldStyle.Render("Code:"))
+	}
+	return details.String()
+}
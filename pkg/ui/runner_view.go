@@ -931,29 +931,6 @@ func (rv *RunnerView) formatTransactionResult(result *overflow.OverflowResult) s
 	return b.String()
 }
 
-// detectNetwork determines the network from a filename
-func (rv *RunnerView) detectNetwork(filename string) string {
-	// Check for network suffixes
-	if strings.HasSuffix(filename, ".emulator") {
-		return "emulator"
-	} else if strings.HasSuffix(filename, ".testnet") {
-		return "testnet"
-	} else if strings.HasSuffix(filename, ".mainnet") {
-		return "mainnet"
-	}
-	return "any"
-}
-
-// removeNetworkSuffix removes network suffix from filename for display
-func (rv *RunnerView) removeNetworkSuffix(filename string) string {
-	for _, suffix := range []string{".emulator", ".testnet", ".mainnet"} {
-		if strings.HasSuffix(filename, suffix) {
-			return strings.TrimSuffix(filename, suffix)
-		}
-	}
-	return filename
-}
-
 // refreshDetailContent updates the detail content for the current row
 func (rv *RunnerView) refreshDetailContent(idx int, script ScriptFile) {
 	content := rv.buildScriptDetail(script)
@@ -1194,44 +1171,19 @@ func (rv *RunnerView) executeScript(script ScriptFile) tea.Cmd {
 func (rv *RunnerView) scanFiles() {
 	var files []ScriptFile
 
-	// Paths to scan
-	scriptDirs := []string{"scripts", "cadence/scripts"}
-	txDirs := []string{"transactions", "cadence/transactions"}
-
 	// Scan scripts
-	for _, dir := range scriptDirs {
+	for _, dir := range flow.ScriptDirs {
 		rv.scanDirectory(dir, TypeScript, &files)
 	}
 
 	// Scan transactions
-	for _, dir := range txDirs {
+	for _, dir := range flow.TransactionDirs {
 		rv.scanDirectory(dir, TypeTransaction, &files)
 	}
 
 	rv.scripts = files
 }
 
-// findCdcFile finds a .cdc file by name in the appropriate directory
-func (rv *RunnerView) findCdcFile(name string, scriptType ScriptType) string {
-	var searchDirs []string
-	if scriptType == TypeScript {
-		searchDirs = []string{"scripts", "cadence/scripts"}
-	} else {
-		searchDirs = []string{"transactions", "cadence/transactions"}
-	}
-
-	for _, dir := range searchDirs {
-		// Try with and without network suffixes
-		for _, suffix := range []string{"", ".emulator", ".testnet", ".mainnet"} {
-			path := filepath.Join(dir, name+suffix+".cdc")
-			if _, err := os.Stat(path); err == nil {
-				return path
-			}
-		}
-	}
-	return ""
-}
-
 // scanDirectory scans a directory for .cdc and .json files
 func (rv *RunnerView) scanDirectory(dir string, scriptType ScriptType, files *[]ScriptFile) {
 	// Check if directory exists
@@ -1273,7 +1225,11 @@ func (rv *RunnerView) scanDirectory(dir string, scriptType ScriptType, files *[]
 				Msg("Loaded JSON config - looking for .cdc file")
 
 			// Find the referenced .cdc file
-			cdcPath := rv.findCdcFile(config.Name, scriptType)
+			searchDirs := flow.TransactionDirs
+			if scriptType == TypeScript {
+				searchDirs = flow.ScriptDirs
+			}
+			cdcPath := flow.FindCdcFile(config.Name, searchDirs)
 			if cdcPath == "" {
 				rv.logger.Debug().
 					Str("jsonPath", path).
@@ -1293,7 +1249,7 @@ func (rv *RunnerView) scanDirectory(dir string, scriptType ScriptType, files *[]
 			}
 
 			// Detect network from config name
-			configNetwork := rv.detectNetwork(config.Name)
+			configNetwork := flow.DetectNetwork(config.Name)
 
 			// Use JSON filename (without extension) as display name to make configs unique
 			// The actual execution name (config.Name) is stored in the Config object
@@ -1349,9 +1305,9 @@ func (rv *RunnerView) scanDirectory(dir string, scriptType ScriptType, files *[]
 		// Detect network from filename suffix (use base filename for detection)
 		basename := filepath.Base(path)
 		basenameWithoutExt := strings.TrimSuffix(basename, ".cdc")
-		network := rv.detectNetwork(basenameWithoutExt)
+		network := flow.DetectNetwork(basenameWithoutExt)
 		// Remove network suffix from display name if present
-		displayName := rv.removeNetworkSuffix(name)
+		displayName := flow.RemoveNetworkSuffix(name)
 
 		script := ScriptFile{
 			Name:            displayName,
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/bjartek/aether/pkg/aether"
 	"github.com/bjartek/aether/pkg/config"
@@ -72,6 +73,11 @@ func DefaultTransactionsKeyMap() TransactionsKeyMap {
 	}
 }
 
+// SelectTransactionMsg selects the row of a transaction, e.g. when jumping to it from the Profile tab
+type SelectTransactionMsg struct {
+	TransactionID string
+}
+
 // TransactionsView is the splitview-based implementation
 type TransactionsView struct {
 	sv               *splitview.SplitViewModel
@@ -89,6 +95,10 @@ type TransactionsView struct {
 	saveError        string                   // Error message from last save attempt
 	saveSuccess      string                   // Success message from last save
 	txSourceMap      map[string]txSourceInfo  // Maps transaction ID to source info
+	sortOrder        string                   // "asc" or "desc", how rows are ordered relative to transactions
+	maxTransactions  int                      // ui.history.max_transactions, older ones are paged in from the history
+	history          historyPager             // Place in the history of every transaction
+	seen             map[string]bool          // IDs of the transactions shown
 	logger           zerolog.Logger           // Debug logger
 }
 
@@ -151,6 +161,9 @@ func NewTransactionsViewWithConfig(cfg *config.Config, logger zerolog.Logger) *T
 		timeFormat:       cfg.UI.Defaults.TimeFormat,
 		saveInput:        saveInput,
 		txSourceMap:      make(map[string]txSourceInfo),
+		sortOrder:        cfg.UI.Defaults.Sort,
+		maxTransactions:  cfg.UI.History.MaxTransactions,
+		seen:             make(map[string]bool),
 		logger:           logger,
 	}
 }
@@ -169,13 +182,38 @@ func (tv *TransactionsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case aether.BlockTransactionMsg:
 		// Handle incoming transaction data
-		tv.AddTransaction(msg.TransactionData)
+		tv.AddTransaction(msg.TransactionData, msg.HistorySeq)
+		return tv, nil
+
+	case aether.HistoryLoadedMsg:
+		// Transactions of earlier runs, shown before the ones indexed since
+		tv.history.history = msg.History
+		tv.prependTransactions(msg.Transactions)
+		return tv, nil
+
+	case transactionsPageMsg:
+		if msg.err != nil {
+			tv.logger.Debug().Err(msg.err).Msg("Failed to read transactions from the history")
+		}
+		if tv.history.loaded(msg.before, len(msg.transactions), msg.err) {
+			tv.prependTransactions(msg.transactions)
+		}
+		return tv, nil
+
+	case aether.IndexerResetMsg:
+		// Everything is indexed again, source info is kept since transaction IDs do not change
+		tv.transactions = nil
+		tv.seen = make(map[string]bool)
+		tv.history.reset()
+		tv.sv.SetRows([]splitview.RowData{})
 		return tv, nil
 
 	case aether.OverflowReadyMsg:
 		// Set overflow and account registry when ready
 		tv.SetOverflow(msg.Overflow)
 		tv.SetAccountRegistry(msg.AccountRegistry)
+		// Transactions loaded from the history before were shown without names
+		tv.refreshAllRows()
 		return tv, nil
 
 	case aether.TransactionSourceMsg:
@@ -201,6 +239,10 @@ func (tv *TransactionsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return tv, nil
 
+	case SelectTransactionMsg:
+		tv.selectTransaction(msg.TransactionID)
+		return tv, nil
+
 	case tea.KeyMsg:
 		// Handle save dialog input
 		if tv.savingMode {
@@ -265,9 +307,33 @@ func (tv *TransactionsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	_, cmd := tv.sv.Update(msg)
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && tv.scrolledToOldest(keyMsg) {
+		cmd = tea.Batch(cmd, tv.loadOlderTransactions())
+	}
 	return tv, cmd
 }
 
+// scrolledToOldest reports whether a key moved the cursor to the oldest transaction, or tried to move past it
+func (tv *TransactionsView) scrolledToOldest(msg tea.KeyMsg) bool {
+	if tv.sv.IsFullscreen() || len(tv.transactions) == 0 {
+		return false
+	}
+	if tv.sortOrder == "desc" {
+		return tv.sv.GetCursor() == len(tv.transactions)-1 &&
+			(key.Matches(msg, tv.keys.LineDown) || key.Matches(msg, tv.keys.GotoEnd))
+	}
+	return tv.sv.GetCursor() == 0 && (key.Matches(msg, tv.keys.LineUp) || key.Matches(msg, tv.keys.GotoTop))
+}
+
+// loadOlderTransactions reads the transactions before the oldest one shown from the history
+func (tv *TransactionsView) loadOlderTransactions() tea.Cmd {
+	h := tv.history.history
+	return tv.history.load(func(before uint64) tea.Msg {
+		transactions, err := h.Transactions(before, historyPageSize)
+		return transactionsPageMsg{before: before, transactions: transactions, err: err}
+	})
+}
+
 // View delegates to splitview
 func (tv *TransactionsView) View() string {
 	tv.logger.Debug().Str("method", "View").Msg("TransactionsView.View called")
@@ -337,6 +403,18 @@ func (tv *TransactionsView) IsCapturingInput() bool {
 	return tv.savingMode
 }
 
+// selectTransaction moves the cursor to the transaction with the given ID, if it has been indexed
+func (tv *TransactionsView) selectTransaction(id string) {
+	for i, tx := range tv.transactions {
+		if tx.ID != id {
+			continue
+		}
+		tv.sv.SetCursor(tv.rowIndex(i))
+		return
+	}
+	tv.logger.Debug().Str("txID", id).Msg("Transaction to select is not indexed")
+}
+
 // SetAccountRegistry sets the account registry for name resolution
 func (tv *TransactionsView) SetAccountRegistry(registry *aether.AccountRegistry) {
 	tv.accountRegistry = registry
@@ -347,23 +425,115 @@ func (tv *TransactionsView) SetOverflow(o *overflow.OverflowState) {
 	tv.overflow = o
 }
 
-// AddTransaction accepts prebuilt TransactionData and converts it to a splitview row
-func (tv *TransactionsView) AddTransaction(txData aether.TransactionData) {
+// rowIndex returns the row of the transaction at index i of the stored transactions
+func (tv *TransactionsView) rowIndex(i int) int {
+	if tv.sortOrder == "desc" {
+		// Newest first, so rows are in reverse order of the stored transactions
+		return len(tv.transactions) - 1 - i
+	}
+	return i
+}
+
+// AddTransaction accepts prebuilt TransactionData and converts it to a splitview row.
+// historySeq is its place in the history, 0 if it is not stored.
+func (tv *TransactionsView) AddTransaction(txData aether.TransactionData, historySeq uint64) {
 	// Check if we have source info for this transaction
 	if sourceInfo, exists := tv.txSourceMap[txData.ID]; exists {
 		txData.SourceFile = sourceInfo.SourceFile
 		txData.IsInit = sourceInfo.IsInit
 	}
 
+	// Indexed again while it is shown from the history, e.g. after restarting a persisted emulator
+	if tv.seen[txData.ID] {
+		tv.replaceTransaction(txData, historySeq)
+		return
+	}
+	tv.seen[txData.ID] = true
+
 	// Store transaction data for rebuilding (always append to internal array)
 	tv.transactions = append(tv.transactions, txData)
+	tv.history.add(historySeq)
 
 	// Add row to splitview (it handles sort order internally)
 	tv.addTransactionRow(txData)
+
+	// Only ui.history.max_transactions are kept, older ones can be paged in again from the history
+	if len(tv.transactions) > tv.maxTransactions {
+		tv.dropOldestTransaction()
+	}
+}
+
+// replaceTransaction updates a transaction that is shown already
+func (tv *TransactionsView) replaceTransaction(txData aether.TransactionData, historySeq uint64) {
+	for i, tx := range tv.transactions {
+		if tx.ID != txData.ID {
+			continue
+		}
+		tv.transactions[i] = txData
+		tv.history.seqs[i] = historySeq
+		tv.sv.UpdateRow(tv.rowIndex(i), tv.transactionRow(txData))
+		return
+	}
+}
+
+// dropOldestTransaction removes the oldest transaction from the view
+func (tv *TransactionsView) dropOldestTransaction() {
+	delete(tv.seen, tv.transactions[0].ID)
+	tv.transactions = tv.transactions[1:]
+	tv.history.dropOldest(1)
+
+	rows := tv.sv.GetRows()
+	cursor := tv.sv.GetCursor()
+	if tv.sortOrder == "desc" {
+		tv.sv.SetRows(rows[:len(rows)-1])
+	} else {
+		tv.sv.SetRows(rows[1:])
+		cursor-- // Keep the same transaction selected
+	}
+	tv.sv.SetCursor(max(cursor, 0))
+}
+
+// prependTransactions adds transactions from the history before the oldest one shown, skipping the ones shown already
+func (tv *TransactionsView) prependTransactions(msgs []aether.BlockTransactionMsg) {
+	older := make([]aether.TransactionData, 0, len(msgs))
+	seqs := make([]uint64, 0, len(msgs))
+	rows := make([]splitview.RowData, 0, len(msgs))
+	for _, msg := range msgs {
+		if tv.seen[msg.TransactionData.ID] {
+			continue
+		}
+		tv.seen[msg.TransactionData.ID] = true
+		older = append(older, msg.TransactionData)
+		seqs = append(seqs, msg.HistorySeq)
+		rows = append(rows, tv.transactionRow(msg.TransactionData))
+	}
+	if len(older) == 0 {
+		return
+	}
+	tv.transactions = append(older, tv.transactions...)
+	tv.history.prepend(seqs)
+
+	cursor := tv.sv.GetCursor()
+	current := tv.sv.GetRows()
+	if tv.sortOrder == "desc" {
+		// Newest first, so the older transactions go below the others
+		slices.Reverse(rows)
+		tv.sv.SetRows(append(current, rows...))
+	} else {
+		tv.sv.SetRows(append(rows, current...))
+		cursor += len(rows) // Keep the same transaction selected
+	}
+	tv.sv.SetCursor(cursor)
 }
 
 // addTransactionRow builds a splitview row from transaction data
 func (tv *TransactionsView) addTransactionRow(txData aether.TransactionData) {
+	// Add to splitview
+	tv.sv.AddRow(tv.transactionRow(txData))
+}
+
+// transactionRow builds the splitview row of a transaction
+func (tv *TransactionsView) transactionRow(txData aether.TransactionData) splitview.RowData {
 	// Build table row
 	authDisplay := "N/A"
 	if len(txData.Authorizers) > 0 {
@@ -410,8 +580,7 @@ func (tv *TransactionsView) addTransactionRow(txData aether.TransactionData) {
 	content := buildTransactionDetailContent(txData, tv.accountRegistry, tv.showEventFields, tv.showRawAddresses)
 	code := buildTransactionDetailCode(txData)
 
-	// Add to splitview
-	tv.sv.AddRow(splitview.NewRowData(row).WithContent(content).WithCode(code))
+	return splitview.NewRowData(row).WithContent(content).WithCode(code)
 }
 
 func truncateHex(s string, startLen, endLen int) string {
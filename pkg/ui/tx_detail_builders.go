@@ -134,10 +134,10 @@ func buildTransactionDetailContent(tx aether.TransactionData, registry *aether.A
 			details.WriteString(fmt.Sprintf("     Gas Used:   %d\n", evmTx.Receipt.GasUsed))
 
 			if from, err := evmTx.Transaction.From(); err == nil {
-				details.WriteString(fmt.Sprintf("     From:       %s\n", from.Hex()))
+				details.WriteString(fmt.Sprintf("     From:       %s\n", formatEVMAddress(from.Hex(), registry, showRaw)))
 			}
 			if to := evmTx.Transaction.To(); to != nil {
-				details.WriteString(fmt.Sprintf("     To:         %s\n", to.Hex()))
+				details.WriteString(fmt.Sprintf("     To:         %s\n", formatEVMAddress(to.Hex(), registry, showRaw)))
 			}
 
 			// Display value if non-zero
@@ -160,7 +160,7 @@ func buildTransactionDetailContent(tx aether.TransactionData, registry *aether.A
 			if len(evmTx.Receipt.Logs) > 0 {
 				details.WriteString(fmt.Sprintf("     Logs:       %d\n", len(evmTx.Receipt.Logs)))
 				for logIdx, log := range evmTx.Receipt.Logs {
-					details.WriteString(fmt.Sprintf("       %d. Address: %s\n", logIdx+1, log.Address.Hex()))
+					details.WriteString(fmt.Sprintf("       %d. Address: %s\n", logIdx+1, formatEVMAddress(log.Address.Hex(), registry, showRaw)))
 					if len(log.Topics) > 0 {
 						details.WriteString(fmt.Sprintf("          Topics: %d\n", len(log.Topics)))
 						for topicIdx, topic := range log.Topics {
@@ -210,6 +210,14 @@ func buildTransactionDetailContent(tx aether.TransactionData, registry *aether.A
 	return details.String()
 }
 
+// formatEVMAddress shows the name of a known EVM address next to it, e.g. alice-evm (0xf39F...)
+func formatEVMAddress(address string, registry *aether.AccountRegistry, showRaw bool) string {
+	if showRaw || registry == nil {
+		return address
+	}
+	return registry.FormatAddress(address)
+}
+
 // buildTransactionDetailCode returns the script body (highlighted when available) with trailing newline.
 func buildTransactionDetailCode(tx aether.TransactionData) string {
 	if tx.Script == "" {
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/flow"
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+)
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// parseInterspersed parses flags that may appear both before and after positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// RunResult is the JSON printed by `aether run`
+type RunResult struct {
+	Name            string                              `json:"name"`
+	Type            string                              `json:"type"`
+	Path            string                              `json:"path,omitempty"`
+	Success         bool                                `json:"success"`
+	Error           string                              `json:"error,omitempty"`
+	Result          interface{}                         `json:"result,omitempty"`
+	TransactionID   string                              `json:"transactionId,omitempty"`
+	ComputationUsed int                                 `json:"computationUsed,omitempty"`
+	Events          map[string][]map[string]interface{} `json:"events,omitempty"`
+}
+
+// runCommand implements `aether run <target>`
+func runCommand(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: aether run [flags] <name|file.cdc|config.json>\n\n")
+		fmt.Fprintf(fs.Output(), "Runs a script or transaction the same way the Runner tab does and prints the result as JSON.\n\n")
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "Path to configuration file")
+	network := fs.String("n", "", "Network to run against: emulator, testnet, or mainnet (overrides config)")
+	var signers, arguments stringList
+	fs.Var(&signers, "signer", "Signer name from flow.json, can be repeated (overrides signers from a json config)")
+	fs.Var(&arguments, "arg", "Argument as name=value, can be repeated (overrides arguments from a json config)")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	result := run(positional[0], *configPath, *network, signers, arguments)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode result: %v\n", err)
+		return 1
+	}
+	if !result.Success {
+		return 1
+	}
+	return 0
+}
+
+func run(targetArg, configPath, network string, signers, arguments []string) RunResult {
+	result := RunResult{Name: targetArg}
+
+	cfg, err := config.Load(configPath, zerolog.Nop())
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to load configuration: %v", err)
+		return result
+	}
+	if network != "" {
+		cfg.Network = network
+	}
+	useRunningPorts(cfg)
+
+	target, err := flow.ResolveTarget(targetArg)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	txConfig := target.Config
+	if len(signers) > 0 {
+		txConfig.Signers = signers
+	}
+	for _, arg := range arguments {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			result.Error = fmt.Sprintf("invalid argument %q, expected name=value", arg)
+			return result
+		}
+		txConfig.Arguments[name] = value
+	}
+
+	result.Name = txConfig.Name
+	result.Path = target.Path
+	result.Type = "transaction"
+	if target.IsScript {
+		result.Type = "script"
+	}
+
+	o := aether.NewRunnerOverflow(cfg, target.BasePath)
+	if o.Error != nil {
+		result.Error = fmt.Sprintf("failed to initialize overflow: %v", o.Error)
+		return result
+	}
+
+	if target.IsScript {
+		res := o.Script(txConfig.Name, txConfig.Options()...)
+		if res.Err != nil {
+			result.Error = res.Err.Error()
+			return result
+		}
+		result.Success = true
+		result.Result = res.Output
+		return result
+	}
+
+	res := o.Tx(txConfig.Name, txConfig.Options()...)
+	if res.Id != flowsdk.EmptyID {
+		result.TransactionID = res.Id.String()
+	}
+	if res.Err != nil {
+		result.Error = res.Err.Error()
+		return result
+	}
+	result.Success = true
+	result.ComputationUsed = res.ComputationUsed
+	if len(res.Events) > 0 {
+		result.Events = make(map[string][]map[string]interface{}, len(res.Events))
+		for name, list := range res.Events {
+			for _, event := range list {
+				result.Events[name] = append(result.Events[name], event.Fields)
+			}
+		}
+	}
+	return result
+}
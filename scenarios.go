@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/scenario"
+	"github.com/rs/zerolog"
+)
+
+// testCommand implements `aether test [scenario files or folders]`
+func testCommand(args []string) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: aether test [flags] [scenario files or folders]\n\n")
+		fmt.Fprintf(fs.Output(), "Runs scenarios against a running emulator and reports pass/fail per step.\n")
+		fmt.Fprintf(fs.Output(), "Without arguments all scenarios in the scenarios folder next to the aether folder are run.\n\n")
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "Path to configuration file")
+	network := fs.String("n", "", "Network to run against: emulator, testnet, or mainnet (overrides config)")
+	jsonOutput := fs.Bool("json", false, "Print results as JSON")
+
+	paths, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	if *network != "" {
+		cfg.Network = *network
+	}
+	useRunningPorts(cfg)
+
+	_, basePath, err := aether.DetectBasePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(paths) == 0 {
+		paths = []string{filepath.Join(basePath, scenario.DefaultFolder)}
+	}
+
+	scenarios, err := loadScenarios(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(scenarios) == 0 {
+		fmt.Fprintf(os.Stderr, "No scenarios found in %v\n", paths)
+		return 1
+	}
+
+	o := aether.NewRunnerOverflow(cfg, basePath)
+	if o.Error != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize overflow: %v\n", o.Error)
+		return 1
+	}
+
+	results := runScenarioFiles(o, scenarios)
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(results)
+	} else {
+		for _, result := range results {
+			result.Print(os.Stdout)
+		}
+	}
+
+	for _, result := range results {
+		if !result.Passed() {
+			return 1
+		}
+	}
+	return 0
+}
+
+// loadScenarios loads scenario files and all scenarios in the given folders
+func loadScenarios(paths []string) ([]*scenario.Scenario, error) {
+	var scenarios []*scenario.Scenario
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			loaded, err := scenario.LoadDir(path)
+			if err != nil {
+				return nil, err
+			}
+			scenarios = append(scenarios, loaded...)
+			continue
+		}
+		s, err := scenario.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+func runScenarioFiles(o scenario.Executor, scenarios []*scenario.Scenario) []scenario.Result {
+	results := make([]scenario.Result, 0, len(scenarios))
+	for _, s := range scenarios {
+		results = append(results, scenario.Run(o, s))
+	}
+	return results
+}
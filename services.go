@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/bjartek/aether/pkg/events"
+	"github.com/bjartek/aether/pkg/flow"
+	"github.com/bjartek/aether/pkg/frontend"
+	"github.com/bjartek/aether/pkg/health"
+	"github.com/bjartek/aether/pkg/logs"
+	devWallet "github.com/onflow/fcl-dev-wallet/go/wallet"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/server"
+	gatewayConfig "github.com/onflow/flow-evm-gateway/config"
+	"github.com/rs/zerolog"
+)
+
+// loggers holds the component specific loggers
+type loggers struct {
+	root     zerolog.Logger
+	aether   zerolog.Logger
+	emulator zerolog.Logger
+	wallet   zerolog.Logger
+	gateway  zerolog.Logger
+}
+
+func newLoggers(logger zerolog.Logger, cfg *config.Config) loggers {
+	return loggers{
+		root:     logger,
+		aether:   logs.WithComponent(logger, "aether").Level(logs.ParseLogLevel(cfg.Logging.Level.Aether)),
+		emulator: logs.WithComponent(logger, "emulator").Level(logs.ParseLogLevel(cfg.Logging.Level.Emulator)),
+		wallet:   logs.WithComponent(logger, "dev-wallet").Level(logs.ParseLogLevel(cfg.Logging.Level.DevWallet)),
+		gateway:  logs.WithComponent(logger, "evm-gateway").Level(logs.ParseLogLevel(cfg.Logging.Level.EVMGateway)),
+	}
+}
+
+// errEmulatorStopped is returned when a service is restarted after an emulator restart failed
+var errEmulatorStopped = errors.New("the emulator is not running, restart it first")
+
+// restartTimeout bounds how long a restarted emulator may take to accept connections
+const restartTimeout = time.Minute
+
+// services holds the local processes aether runs next to the UI: emulator, dev wallet, EVM gateway and frontend.
+// It is shared between the TUI and the headless modes.
+type services struct {
+	cfg *config.Config
+	log loggers
+
+	// mu guards the processes below while one of them is restarted
+	mu         sync.Mutex
+	emu        *server.EmulatorServer
+	dw         *devWallet.Server
+	gateway    *flow.Gateway
+	gatewayCfg gatewayConfig.Config
+	frontend   *frontend.FrontendManager
+	sink       events.Sink // Receives the ports of a restarted frontend
+
+	// health probes the local services, nil when following a network
+	health      *health.Monitor
+	closeProbes func() error
+	stopHealth  context.CancelFunc
+
+	// ready is closed when the emulator is ready to accept connections
+	ready chan struct{}
+}
+
+// startServices initializes and starts the emulator and dev wallet in emulator mode.
+// In network mode nothing is started and the services are immediately ready.
+func startServices(cfg *config.Config, log loggers) (*services, error) {
+	s := &services{
+		cfg:   cfg,
+		log:   log,
+		ready: make(chan struct{}),
+	}
+
+	if !config.IsEmulator(cfg.Network) {
+		// Network mode: following testnet or mainnet
+		log.aether.Info().Str("network", cfg.Network).Msg("Following network - no local services will be started")
+		close(s.ready) // Immediately ready since we're not starting an emulator
+		return s, nil
+	}
+
+	// Local mode: start emulator, dev wallet, and EVM gateway
+	log.aether.Info().Msg("Initializing Flow emulator, dev wallet, and EVM gateway...")
+	emu, dw, err := flow.InitEmulator(&log.emulator, cfg)
+	if err != nil {
+		log.aether.Error().Err(err).Msg("Failed to initialize Flow emulator & dev wallet")
+		return nil, err
+	}
+	s.emu = emu
+	s.dw = dw
+
+	// The EVM gateway needs the EVM contracts deployed in the emulator
+	if cfg.Flow.Emulator.SetupEVMEnabled {
+		log.aether.Info().Msg("Initializing EVM gateway...")
+		gateway, gatewayCfg, err := flow.InitGateway(log.gateway, cfg)
+		if err != nil {
+			log.aether.Error().Err(err).Msg("Failed to initialize EVM gateway")
+			return nil, err
+		}
+		s.gateway = gateway
+		s.gatewayCfg = gatewayCfg
+		log.aether.Info().Msg("EVM gateway initialization complete")
+	} else {
+		log.aether.Info().Msg("EVM setup is disabled in the emulator - not starting the EVM gateway")
+	}
+	log.aether.Info().Msg("All initialization complete")
+
+	checks, closeProbes, err := healthChecks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.health = health.NewMonitor(checks, log.aether)
+	s.closeProbes = closeProbes
+	s.stopHealth = cancel
+	s.health.Start(ctx)
+
+	// Start emulator in background
+	log.emulator.Info().Msg("Starting Flow emulator...")
+	go func() {
+		emu.Start()
+		log.emulator.Info().Msg("Emulator stopped")
+	}()
+
+	// Everything else talks to the emulator through its access API
+	go func() {
+		if err := s.health.WaitReady(ctx, health.EmulatorGRPC); err != nil {
+			return
+		}
+		log.emulator.Info().Msg("Emulator is ready")
+		close(s.ready)
+	}()
+
+	// Start dev wallet in background
+	go s.runDevWallet(dw)
+
+	// Start EVM gateway after emulator is ready
+	if s.gateway != nil {
+		go s.runGateway(s.gateway, s.gatewayCfg, s.ready)
+	}
+
+	return s, nil
+}
+
+// runDevWallet runs the dev wallet until it is stopped
+func (s *services) runDevWallet(dw *devWallet.Server) {
+	s.log.wallet.Info().Msg("Starting dev wallet...")
+	if err := dw.Start(); err != nil {
+		s.log.wallet.Error().Err(err).Msg("Dev wallet stopped with error")
+	}
+}
+
+// runGateway starts the EVM gateway once ready is closed and waits for it to stop
+func (s *services) runGateway(gateway *flow.Gateway, cfg gatewayConfig.Config, ready <-chan struct{}) {
+	<-ready
+	s.log.gateway.Info().Msg("Starting EVM gateway...")
+	gateway.Start(cfg)
+
+	// The gateway keeps running until aether stops it, anything else means it exited on its own
+	<-gateway.Done()
+	if !gateway.Stopping() {
+		s.health.MarkStopped(health.EVMGatewayRPC)
+		s.health.MarkStopped(health.EVMGatewayPprof)
+	}
+}
+
+// healthChecks returns the probes of the local services, the close func releases their connections
+func healthChecks(cfg *config.Config) ([]health.Check, func() error, error) {
+	local := func(port int) string {
+		return fmt.Sprintf("127.0.0.1:%d", port)
+	}
+
+	accessProbe, closeProbes, err := flow.AccessProbe(local(cfg.Ports.Emulator.GRPC))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checks := []health.Check{
+		{Name: health.EmulatorGRPC, Port: cfg.Ports.Emulator.GRPC, Probe: accessProbe},
+		{Name: health.EmulatorREST, Port: cfg.Ports.Emulator.REST, Probe: health.HTTPProbe("http://" + local(cfg.Ports.Emulator.REST) + "/v1/blocks?height=sealed")},
+		{Name: health.EmulatorAdmin, Port: cfg.Ports.Emulator.Admin, Probe: health.HTTPProbe("http://" + local(cfg.Ports.Emulator.Admin) + "/")},
+		{Name: health.EmulatorDebugger, Port: cfg.Ports.Emulator.Debugger, Probe: health.TCPProbe(local(cfg.Ports.Emulator.Debugger))},
+		{Name: health.DevWallet, Port: cfg.Ports.DevWallet, Probe: health.HTTPProbe("http://" + local(cfg.Ports.DevWallet) + "/")},
+	}
+	if cfg.Flow.Emulator.SetupEVMEnabled {
+		checks = append(checks,
+			health.Check{Name: health.EVMGatewayRPC, Port: cfg.Ports.EVM.RPC, Probe: health.JSONRPCProbe("http://" + local(cfg.Ports.EVM.RPC))},
+			health.Check{Name: health.EVMGatewayPprof, Port: cfg.Ports.EVM.Profiler, Probe: health.TCPProbe(local(cfg.Ports.EVM.Profiler))},
+		)
+	}
+	return checks, closeProbes, nil
+}
+
+// watchHealth sends the status of the local services to sink, nothing is sent when following a network
+func (s *services) watchHealth(sink events.Sink) {
+	if s.health != nil {
+		s.health.AddSink(sink)
+	}
+}
+
+// emulator returns the running emulator, or nil when following a network
+func (s *services) emulator() emulator.Emulator {
+	if s.emu == nil {
+		return nil
+	}
+	return s.emu.Emulator()
+}
+
+// startFrontend starts the configured frontend command once the emulator is ready
+func (s *services) startFrontend(sink events.Sink) {
+	if s.cfg.FrontendCommand == "" {
+		return
+	}
+	s.frontend = frontend.NewFrontendManager(s.cfg.FrontendCommand, s.log.root)
+	s.sink = sink
+	go func() {
+		<-s.ready // Wait for emulator to be ready
+		// A frontend usually connects to the dev wallet right away
+		if s.health != nil {
+			<-s.health.Ready(health.DevWallet)
+		}
+		if err := s.frontend.Start(sink); err != nil {
+			s.log.root.Error().Err(err).Msg("Failed to start frontend process")
+		}
+	}()
+}
+
+// stop shuts down all services that were started
+func (s *services) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if config.IsEmulator(s.cfg.Network) {
+		// Only stop local services if they were started
+		// After a failed restart nothing runs anymore
+		if s.gateway != nil {
+			s.log.gateway.Info().Msg("Stopping EVM gateway...")
+			s.gateway.Stop()
+			s.log.gateway.Info().Msg("EVM gateway stopped")
+		}
+		if s.emu != nil {
+			s.log.emulator.Info().Msg("Stopping emulator...")
+			s.emu.Stop()
+		}
+		if s.dw != nil {
+			s.log.wallet.Info().Msg("Stopping dev wallet...")
+			s.dw.Stop()
+		}
+
+		for _, st := range s.health.Statuses() {
+			s.health.MarkStopped(st.Service)
+		}
+		s.stopHealth()
+		_ = s.closeProbes()
+	}
+	if s.frontend != nil {
+		if err := s.frontend.Stop(); err != nil {
+			s.log.aether.Error().Err(err).Msg("Failed to stop frontend process")
+		} else {
+			s.log.aether.Info().Msg("Frontend process stopped")
+		}
+	}
+}
+
+// controller returns the services as an aether.ServiceController, nil when following a network
+func (s *services) controller() aether.ServiceController {
+	if s.emu == nil {
+		return nil
+	}
+	return s
+}
+
+// RestartEmulator implements aether.ServiceController. The emulator starts over from its
+// configured state, the dev wallet and EVM gateway are restarted against the new emulator.
+// If that fails everything is stopped, the emulator can be restarted again.
+func (s *services) RestartEmulator() (emulator.Emulator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.gateway != nil {
+		s.stopGateway()
+		s.gateway = nil
+	}
+	// Not running anymore if the last restart failed
+	if s.emu != nil {
+		s.log.emulator.Info().Msg("Stopping emulator for restart...")
+		s.emu.Stop()
+		s.dw.Stop()
+	}
+
+	// Everything that talks to the emulator is restarted with it
+	for _, st := range s.health.Statuses() {
+		s.health.MarkRestarting(st.Service)
+	}
+
+	s.emu, s.dw = nil, nil
+	emu, dw, err := flow.InitEmulator(&s.log.emulator, s.cfg)
+	if err != nil {
+		return nil, s.restartFailed(fmt.Errorf("failed to initialize emulator: %w", err))
+	}
+	s.emu = emu
+	s.dw = dw
+
+	s.log.emulator.Info().Msg("Starting Flow emulator...")
+	go func() {
+		emu.Start()
+		s.log.emulator.Info().Msg("Emulator stopped")
+	}()
+	go s.runDevWallet(dw)
+
+	ctx, cancel := context.WithTimeout(context.Background(), restartTimeout)
+	defer cancel()
+	if err := s.health.WaitReady(ctx, health.EmulatorGRPC); err != nil {
+		return nil, s.restartFailed(fmt.Errorf("emulator did not become ready: %w", err))
+	}
+	s.log.emulator.Info().Msg("Emulator is ready")
+
+	if s.cfg.Flow.Emulator.SetupEVMEnabled {
+		if err := s.startGateway(); err != nil {
+			return nil, s.restartFailed(err)
+		}
+	}
+	return emu.Emulator(), nil
+}
+
+// restartFailed stops what a failed emulator restart started already and marks every service stopped with err,
+// so the dashboard shows that nothing runs. The caller holds mu.
+func (s *services) restartFailed(err error) error {
+	if s.emu != nil {
+		s.emu.Stop()
+	}
+	if s.dw != nil {
+		s.dw.Stop()
+	}
+	s.emu, s.dw, s.gateway = nil, nil, nil
+
+	for _, st := range s.health.Statuses() {
+		s.health.MarkFailed(st.Service, err)
+	}
+	s.log.emulator.Error().Err(err).Msg("Emulator restart failed, the local services are stopped")
+	return err
+}
+
+// RestartGateway implements aether.ServiceController
+func (s *services) RestartGateway() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.emu == nil {
+		return errEmulatorStopped
+	}
+	if s.gateway == nil {
+		return fmt.Errorf("the EVM gateway is not running, is evm setup enabled in the emulator?")
+	}
+	s.stopGateway()
+	return s.startGateway()
+}
+
+// RestartDevWallet implements aether.ServiceController
+func (s *services) RestartDevWallet() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.emu == nil {
+		return errEmulatorStopped
+	}
+	s.log.wallet.Info().Msg("Stopping dev wallet for restart...")
+	s.dw.Stop()
+	s.health.MarkRestarting(health.DevWallet)
+
+	dw, err := flow.InitDevWallet(s.cfg, s.emu)
+	if err != nil {
+		return fmt.Errorf("failed to initialize dev wallet: %w", err)
+	}
+	s.dw = dw
+	go s.runDevWallet(dw)
+	return nil
+}
+
+// RestartFrontend implements aether.ServiceController
+func (s *services) RestartFrontend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.frontend == nil {
+		return fmt.Errorf("no frontend command is configured")
+	}
+	if err := s.frontend.Stop(); err != nil {
+		return fmt.Errorf("failed to stop frontend process: %w", err)
+	}
+	s.frontend = frontend.NewFrontendManager(s.cfg.FrontendCommand, s.log.root)
+	return s.frontend.Start(s.sink)
+}
+
+// stopGateway stops the EVM gateway, the caller holds mu
+func (s *services) stopGateway() {
+	s.log.gateway.Info().Msg("Stopping EVM gateway for restart...")
+	s.gateway.Stop()
+}
+
+// startGateway creates a new EVM gateway and starts it, the caller holds mu.
+// InitGateway removes the gateway database first when evm.delete_database_on_start is set.
+func (s *services) startGateway() error {
+	s.health.MarkRestarting(health.EVMGatewayRPC)
+	s.health.MarkRestarting(health.EVMGatewayPprof)
+
+	gateway, gatewayCfg, err := flow.InitGateway(s.log.gateway, s.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize EVM gateway: %w", err)
+	}
+	s.gateway = gateway
+	s.gatewayCfg = gatewayCfg
+
+	// ready was closed when aether started, the emulator is already running again
+	go s.runGateway(gateway, gatewayCfg, s.ready)
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bjartek/aether/pkg/aether"
+	"github.com/bjartek/aether/pkg/config"
+	"github.com/rs/zerolog"
+)
+
+// travelCommand implements `aether travel`: move the emulator of a running aether forward in time or
+// skip blocks, e.g. to test vesting, auctions and scheduled transactions without waiting
+func travelCommand(args []string) int {
+	fs := flag.NewFlagSet("travel", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: aether travel [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Moves the emulator of a running aether forward in time and/or skips blocks.\n\n")
+		fmt.Fprintf(fs.Output(), "Examples:\n  aether travel -by 7d\n  aether travel -blocks 100\n\n")
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "Path to configuration file")
+	by := fs.String("by", "", "Advance the block timestamp by this duration, e.g. 90m, 24h or 7d")
+	blocks := fs.Int("blocks", 0, "Number of blocks to skip ahead")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *by == "" && *blocks == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	useRunningPorts(cfg)
+	client := aether.NewControlClient(cfg)
+
+	if *by != "" {
+		block, err := client.AdvanceTime(*by)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Advanced time by %s, height %d at %s\n", *by, block.Height, block.Timestamp.UTC().Format(time.RFC3339))
+	}
+
+	if *blocks > 0 {
+		block, err := client.CommitBlocks(*blocks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Skipped %d block(s), height %d at %s\n", *blocks, block.Height, block.Timestamp.UTC().Format(time.RFC3339))
+	}
+	return 0
+}